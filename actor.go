@@ -2,13 +2,16 @@ package pctk
 
 import (
 	"log"
+	"slices"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
 
 const (
-	DefaultActorSpeakDelay = 500 * time.Millisecond
+	DefaultActorSpeakDelay     = 500 * time.Millisecond
+	DefaultActorRunFactor      = 2.5
+	DefaultActorFollowDistance = 30
 )
 
 var (
@@ -21,6 +24,7 @@ var (
 )
 
 type Actor struct {
+	Footprint Size      // Size of the area the actor blocks to others while walking, if any
 	Size      Size      // Size of the actor
 	TalkColor Color     // Color of the text when the actor talks
 	UsePos    Position  // Position where other actors interact with this actor
@@ -36,9 +40,14 @@ type Actor struct {
 	lookAt    Direction
 	name      string
 	pos       Positionf
+	queue     []*Action // The actions to do after the current one
 	room      *Room
+	routine   *actorRoutine // The routine of the actor, if any
 	scriptLoc FieldAccessor // The location of the actor in the script
 	speed     Positionf
+	turn      *actorTurn               // The turn the actor is doing, if any
+	walkPath  []Position               // The waypoints the actor is walking through, if any
+	wardrobe  map[ResourceRef]*Costume // The costumes worn by the actor so far
 }
 
 // NewActor creates a new actor with the given ID and name.
@@ -49,10 +58,11 @@ func NewActor(id, name string) *Actor {
 		UsePos:    DefaultActorUsePos,
 		UseDir:    DefaultActorDirection,
 
-		id:    id,
-		name:  name,
-		pos:   DefaultActorPosition.ToPosf(),
-		speed: DefaultActorSpeed,
+		id:       id,
+		name:     name,
+		pos:      DefaultActorPosition.ToPosf(),
+		speed:    DefaultActorSpeed,
+		wardrobe: make(map[ResourceRef]*Costume),
 	}
 }
 
@@ -62,12 +72,18 @@ func (a *Actor) AddToInventory(obj *Object) {
 	obj.owner = a
 }
 
-// CancelAction cancels the current action of the actor.
+// CancelAction cancels the current action of the actor, and the ones queued after it.
 func (a *Actor) CancelAction() {
 	if a.act != nil {
 		a.act.Cancel()
 	}
+	for _, action := range a.queue {
+		action.Cancel()
+	}
 	a.act = nil
+	a.queue = nil
+	a.turn = nil
+	a.walkPath = nil
 }
 
 // Class returns the class of the actor.
@@ -75,19 +91,65 @@ func (a *Actor) Class() ObjectClass {
 	return ObjectClassPerson
 }
 
-// Do executes the action in the actor.
-func (a *Actor) Do(action *Action) Future {
-	if a.act != nil {
-		a.act.Cancel()
+// CostumeRef returns the reference of the costume the actor is wearing, or ResourceRefNull if it
+// has no costume or it was not loaded from a resource.
+func (a *Actor) CostumeRef() ResourceRef {
+	for ref, costume := range a.wardrobe {
+		if costume == a.costume && costume != nil {
+			return ref
+		}
 	}
+	return ResourceRefNull
+}
+
+// Direction returns the direction the actor is facing.
+func (a *Actor) Direction() Direction {
+	return a.lookAt
+}
+
+// Do executes the action in the actor. The current action and the queued ones are cancelled.
+func (a *Actor) Do(action *Action) Future {
+	a.CancelAction()
 	a.act = action
 	return a.act.Done()
 }
 
+// Enqueue executes the action in the actor after the current action and the queued ones are
+// completed. Idle actions, such as standing, are cancelled as soon as there is another action to
+// do.
+func (a *Actor) Enqueue(action *Action) Future {
+	a.queue = append(a.queue, action)
+	return action.Done()
+}
+
+// perform executes the action in the actor, either enqueuing it or replacing the current one.
+func (a *Actor) perform(action *Action, queue bool) Future {
+	if queue {
+		return a.Enqueue(action)
+	}
+	return a.Do(action)
+}
+
 // Draw renders the actor in the viewport.
 func (a *Actor) Draw() {
+	// Idle actions done on purpose, such as following another actor, hold the routine until they
+	// are stopped or replaced.
+	if len(a.queue) == 0 && (a.act == nil || a.act.rest) {
+		if action := a.nextRoutineAction(); action != nil {
+			a.queue = append(a.queue, action)
+		}
+	}
+	if len(a.queue) > 0 && (a.act == nil || a.act.idle) {
+		if a.act != nil {
+			a.act.Cancel()
+		}
+		a.act, a.queue = a.queue[0], a.queue[1:]
+		a.turn = nil
+		a.walkPath = nil
+	}
 	if a.act == nil {
 		a.act = Standing(a.lookAt)
+		a.act.rest = true
 	}
 
 	if a.act.RunFrame(a) {
@@ -95,9 +157,9 @@ func (a *Actor) Draw() {
 	}
 }
 
-// Hotspot returns the hotspot of the actor.
+// Hotspot returns the hotspot of the actor, scaled according to the depth of its position.
 func (a *Actor) Hotspot() Rectangle {
-	return Rectangle{Pos: a.costumePos(), Size: a.Size}
+	return Rectangle{Pos: a.costumePos(), Size: a.scaledSize()}
 }
 
 // ID returns the ID of the actor.
@@ -120,11 +182,17 @@ func (a *Actor) IsSpeaking() bool {
 	return a.dialog != nil && !a.dialog.Done().IsCompleted()
 }
 
+// IsWalking returns true if the actor is walking, false otherwise.
+func (a *Actor) IsWalking() bool {
+	return len(a.walkPath) > 0
+}
+
 // Locate the actor in the given room, position and direction.
 func (a *Actor) Locate(room *Room, pos Position, dir Direction) {
 	a.room = room
 	a.pos = pos.ToPosf()
-	a.Do(Standing(dir))
+	a.lookAt = dir
+	a.CancelAction()
 }
 
 // Name returns the name of the actor.
@@ -151,6 +219,13 @@ func (a *Actor) Room() *Room {
 // SetCostume sets the costume for the actor.
 func (a *Actor) SetCostume(costume *Costume) *Actor {
 	a.costume = costume
+	a.turn = nil
+	return a
+}
+
+// SetSpeed sets the walking speed of the actor, in pixels per second on each axis.
+func (a *Actor) SetSpeed(speed Positionf) *Actor {
+	a.speed = speed
 	return a
 }
 
@@ -164,58 +239,444 @@ func (a *Actor) ScriptLocation() FieldAccessor {
 	return a.scriptLoc
 }
 
+// Speed returns the walking speed of the actor, in pixels per second on each axis.
+func (a *Actor) Speed() Positionf {
+	return a.speed
+}
+
 // UsePosition returns the position where actors interact with the actor.
 func (a *Actor) UsePosition() (Position, Direction) {
 	return a.UsePos, a.UseDir
 }
 
 func (a *Actor) costumePos() Position {
-	return a.pos.ToPos().Sub(NewPos(a.Size.W/2, a.Size.H-a.elev))
+	size := a.scaledSize()
+	elev := int(float32(a.elev) * a.scale())
+	return a.pos.ToPos().Sub(NewPos(size.W/2, size.H-elev))
+}
+
+// drawRect returns the rectangle of the room where the costume of the actor is drawn.
+func (a *Actor) drawRect() Rectangle {
+	if a.costume == nil {
+		return a.Hotspot()
+	}
+	scale := a.scale()
+	size := a.costume.sprites.frameSize
+	return Rectangle{
+		Pos:  a.costumePos(),
+		Size: NewSize(int(float32(size.W)*scale), int(float32(size.H)*scale)),
+	}
 }
 
 func (a *Actor) dialogPos() Position {
-	return a.pos.ToPos().Above(a.Size.H + 40)
+	return a.pos.ToPos().Above(a.scaledSize().H + 40)
+}
+
+func (a *Actor) drawCostume(act CostumeAction) {
+	if cos := a.costume; cos != nil {
+		cos.draw(act, a.costumePos(), a.scale())
+	}
+}
+
+// actorTurn is the progress of an actor turning from one direction to another.
+type actorTurn struct {
+	target Direction   // The direction the actor is turning to
+	steps  []Direction // The directions the actor has still to face in the way
+	anim   *Animation  // The turn animation being played, if any
+}
+
+// turnTowards makes the actor turn to face the given direction, passing through the directions
+// its costume has turn animations for. It returns true while the actor is turning, in which case
+// the turn animation is drawn instead of any other one.
+func (a *Actor) turnTowards(dir Direction) bool {
+	if a.turn == nil || a.turn.target != dir {
+		a.turn = nil
+		steps := a.costume.TurnSteps(a.lookAt, dir)
+		if len(steps) == 0 {
+			a.lookAt = dir
+			return false
+		}
+		a.turn = &actorTurn{target: dir, steps: steps}
+	}
+	if a.turn.anim == nil {
+		a.lookAt = a.turn.steps[0]
+		a.turn.steps = a.turn.steps[1:]
+		a.turn.anim = a.costume.anims[CostumeTurn(a.lookAt)]
+		a.turn.anim.Rewind()
+	}
+	if a.turn.anim.DrawOnce(a.costume.sprites, a.costumePos(), a.scale()) {
+		a.turn.anim = nil
+		if len(a.turn.steps) == 0 {
+			a.lookAt = dir
+			a.turn = nil
+		}
+	}
+	return true
+}
+
+// scale returns the scale of the actor according to the depth of its position in the room.
+func (a *Actor) scale() float32 {
+	scale, _ := a.room.DepthAt(a.pos)
+	return scale
+}
+
+// scaledSize returns the size of the actor scaled according to the depth of its position.
+func (a *Actor) scaledSize() Size {
+	scale := a.scale()
+	return NewSize(int(float32(a.Size.W)*scale), int(float32(a.Size.H)*scale))
+}
+
+// walkSpeed returns the speed of the actor modified by the walk box it is walking through.
+func (a *Actor) walkSpeed() Positionf {
+	_, speed := a.room.DepthAt(a.pos)
+	return a.speed.Scale(speed)
 }
 
 // Action is an action that an actor is performing.
 type Action struct {
-	prom *Promise
-	f    func(*Actor, *Promise)
+	prom   *Promise
+	f      func(*Actor, *Promise)
+	idle   bool         // Idle actions never complete, so they are cancelled when others are queued
+	rest   bool         // Whether the actor does it because it has nothing else to do
+	follow *actorFollow // The actor being followed, if any
+}
+
+// actorFollow describes how an actor follows another one.
+type actorFollow struct {
+	target      *Actor
+	distance    float32
+	acrossRooms bool
+}
+
+// isFollowing returns true if the actor is following the other one. If acrossRooms is true, it
+// returns true only if it follows the other actor to other rooms as well.
+func (a *Actor) isFollowing(other *Actor, acrossRooms bool) bool {
+	if a.act == nil || a.act.follow == nil || a.act.follow.target != other {
+		return false
+	}
+	return a.act.follow.acrossRooms || !acrossRooms
 }
 
 // Standing creates a new action that makes an actor stand in the given direction.
 func Standing(dir Direction) *Action {
+	var fidget fidgeting
 	return &Action{
 		prom: NewPromise(),
+		idle: true,
 		f: func(a *Actor, done *Promise) {
-			a.lookAt = dir
-			costume := CostumeIdle(dir)
+			if a.turnTowards(dir) {
+				fidget.reset()
+				return
+			}
 			if a.IsSpeaking() {
-				costume = CostumeSpeak(dir)
+				fidget.reset()
+				a.drawCostume(CostumeSpeak(dir))
+				return
+			}
+			if fidget.play(a) {
+				return
 			}
-			if cos := a.costume; cos != nil {
-				cos.draw(costume, a.costumePos())
+			a.drawCostume(CostumeIdle(dir))
+		},
+	}
+}
+
+// fidgeting is the progress of the fidgets of an actor standing still.
+type fidgeting struct {
+	costume *Costume   // The costume the fidgets come from
+	next    time.Time  // The time to play the next fidget, or zero if not scheduled yet
+	anim    *Animation // The fidget being played, if any
+}
+
+// play draws the fidget the actor is doing, if any, or starts a new one if it is time to. It
+// returns true if a fidget is drawn.
+func (f *fidgeting) play(a *Actor) bool {
+	if f.costume != a.costume {
+		// The actor changed its costume. Start over.
+		*f = fidgeting{costume: a.costume}
+	}
+	if f.anim != nil {
+		if f.anim.DrawOnce(a.costume.sprites, a.costumePos(), a.scale()) {
+			f.reset()
+		}
+		return true
+	}
+	if f.next.IsZero() {
+		if wait, ok := a.costume.nextFidget(); ok {
+			f.next = time.Now().Add(wait)
+		}
+		return false
+	}
+	if time.Now().Before(f.next) {
+		return false
+	}
+	f.next = time.Time{}
+	if f.anim = a.costume.randomFidget(); f.anim == nil {
+		return false
+	}
+	f.anim.Rewind()
+	return f.play(a)
+}
+
+// reset stops the current fidget, if any, and waits again for the next one.
+func (f *fidgeting) reset() {
+	f.anim = nil
+	f.next = time.Time{}
+}
+
+// facing creates a new action that makes an actor turn to face the given position, from wherever
+// it is when the action starts.
+func facing(pos Position) *Action {
+	var action *Action
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			if action == nil {
+				dir := a.lookAt
+				if pos != a.Position() {
+					dir = a.Position().DirectionTo(pos)
+				}
+				action = Turning(dir)
 			}
+			action.f(a, done)
 		},
 	}
 }
 
-// WalkingTo creates a new action that makes an actor walk to a given position.
+// Waiting creates a new action that makes an actor stand still for the given duration.
+func Waiting(d time.Duration) *Action {
+	var since time.Time
+	var stand *Action
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			if stand == nil {
+				since = time.Now()
+				stand = Standing(a.lookAt)
+			}
+			stand.f(a, done)
+			if time.Since(since) >= d {
+				done.Complete()
+			}
+		},
+	}
+}
+
+// Turning creates a new action that makes an actor turn to face the given direction. It completes
+// once the turn animations of the actor's costume are over.
+func Turning(dir Direction) *Action {
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			if a.turnTowards(dir) {
+				return
+			}
+			a.drawCostume(CostumeIdle(dir))
+			done.Complete()
+		},
+	}
+}
+
+// Following creates a new action that makes an actor follow another one, keeping the given
+// distance. The action never completes, it goes on until it is cancelled. If acrossRooms is true,
+// the actor goes along with the followed one when it is moved to another room.
+func Following(target *Actor, distance int, acrossRooms bool) *Action {
+	follow := &actorFollow{target: target, distance: float32(distance), acrossRooms: acrossRooms}
+	var walk *Action
+	var goal Positionf
+	return &Action{
+		prom:   NewPromise(),
+		idle:   true,
+		follow: follow,
+		f: func(a *Actor, done *Promise) {
+			if target.room == nil || target.room != a.room {
+				// Wait for the followed actor to come back.
+				walk = nil
+				a.walkPath = nil
+				a.drawCostume(CostumeIdle(a.lookAt))
+				return
+			}
+			if a.pos.DistanceTo(&target.pos) <= follow.distance {
+				walk = nil
+				a.walkPath = nil
+				dir := a.lookAt
+				if a.Position() != target.Position() {
+					dir = a.Position().DirectionTo(target.Position())
+				}
+				if !a.turnTowards(dir) {
+					a.drawCostume(CostumeIdle(a.lookAt))
+				}
+				return
+			}
+
+			// Find a new path when the followed actor moves away from the last known position.
+			if walk == nil || goal.DistanceTo(&target.pos) > follow.distance/2 {
+				goal = target.pos
+				walk = WalkingPath(a.room.FindPath(a.Position(), goal.ToPos()))
+			}
+			if walk.RunFrame(a) {
+				walk = nil
+			}
+		},
+	}
+}
+
+// pathTo creates a new action that finds the path to the given position in the room of the actor,
+// and then goes through it with the action returned by walk. The path is found when the action
+// starts, so it departs from wherever the actor is after the actions queued before.
+func pathTo(pos Position, walk func(path []Position) *Action) *Action {
+	var action *Action
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			if action == nil {
+				action = walk(a.room.FindPath(a.Position(), pos))
+			}
+			action.f(a, done)
+		},
+	}
+}
+
+// JumpingTo creates a new action that moves an actor straight to the given position, or as close
+// as possible if other actors are standing there. The actor stays where it is if there is no room
+// for it.
+func JumpingTo(pos Position) *Action {
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			dest, ok := freeDestination(pos.ToPosf(), a.obstacles(), a.walkable)
+			if ok && dest.ToPos() != a.pos.ToPos() {
+				a.lookAt = a.pos.ToPos().DirectionTo(dest.ToPos())
+				a.pos = dest
+			}
+			a.drawCostume(CostumeIdle(a.lookAt))
+			done.Complete()
+		},
+	}
+}
+
+// Animating creates a new action that makes an actor play the animation of its costume for the
+// given action. Looping animations are played until another action is done. Otherwise, the
+// action completes when the animation ends.
+func Animating(act CostumeAction, loop bool) *Action {
+	started := false
+	return &Action{
+		prom: NewPromise(),
+		idle: loop,
+		f: func(a *Actor, done *Promise) {
+			cos := a.costume
+			if cos == nil {
+				done.Complete()
+				return
+			}
+			if loop {
+				a.drawCostume(act)
+				return
+			}
+			anim := cos.animation(act)
+			if !started {
+				anim.Rewind()
+				started = true
+			}
+			if anim.DrawOnce(cos.sprites, a.costumePos(), a.scale()) {
+				done.Complete()
+			}
+		},
+	}
+}
+
+// WalkingTo creates a new action that makes an actor walk straight to a given position.
 func WalkingTo(pos Position) *Action {
+	return WalkingPath([]Position{pos})
+}
+
+// WalkingPath creates a new action that makes an actor walk through the given waypoints.
+func WalkingPath(path []Position) *Action {
+	return walkingPath(path, 1)
+}
+
+// RunningPath creates a new action that makes an actor run through the given waypoints. Running
+// is like walking, but DefaultActorRunFactor times faster.
+func RunningPath(path []Position) *Action {
+	return walkingPath(path, DefaultActorRunFactor)
+}
+
+func walkingPath(path []Position, factor float32) *Action {
 	return &Action{
 		prom: NewPromise(),
 		f: func(a *Actor, done *Promise) {
-			if cos := a.costume; cos != nil {
-				cos.draw(CostumeWalk(a.lookAt), a.costumePos())
+			for len(path) > 0 && a.pos.ToPos() == path[0] {
+				path = path[1:]
+			}
+			a.walkPath = path
+			if len(path) == 0 {
+				a.drawCostume(CostumeWalk(a.lookAt))
+				done.Complete()
+				return
 			}
 
-			if a.pos.ToPos() == pos {
+			// Other actors might be in the way, or even at the destination. Check it every frame
+			// since they can move as well.
+			obstacles := a.obstacles()
+			if len(path) > 1 && slices.ContainsFunc(obstacles, func(o obstacle) bool {
+				return o.contains(path[0].ToPosf())
+			}) {
+				// Going straight to the next waypoint might leave the walkable area. Go round the
+				// actor in the way, or stop here if there is no room to do so.
+				waypoint, ok := detour(a.pos, path[0].ToPosf(), path[1].ToPosf(), obstacles, a.walkable)
+				if !ok {
+					a.drawCostume(CostumeWalk(a.lookAt))
+					done.Complete()
+					return
+				}
+				path = append([]Position{waypoint.ToPos()}, path[1:]...)
+				a.walkPath = path
+			}
+			if len(path) == 1 {
+				dest, ok := freeDestination(path[0].ToPosf(), obstacles, a.walkable)
+				if !ok {
+					a.drawCostume(CostumeWalk(a.lookAt))
+					done.Complete()
+					return
+				}
+				path = []Position{dest.ToPos()}
+			}
+			target, ok := steer(a.pos, path[0].ToPosf(), obstacles, a.walkable)
+			if !ok {
+				// There is no way to go around the obstacle. Stop here.
+				a.drawCostume(CostumeWalk(a.lookAt))
 				done.Complete()
 				return
 			}
 
-			a.lookAt = a.pos.ToPos().DirectionTo(pos)
-			a.pos = a.pos.Move(pos.ToPosf(), a.speed.Scale(rl.GetFrameTime()))
+			// Actors do not walk while turning to a new direction.
+			if target.ToPos() != a.pos.ToPos() {
+				if a.turnTowards(a.pos.ToPos().DirectionTo(target.ToPos())) {
+					return
+				}
+			}
+			a.drawCostume(CostumeWalk(a.lookAt))
+			a.pos = a.pos.Move(target, a.walkSpeed().Scale(factor*rl.GetFrameTime()))
+		},
+	}
+}
+
+// Speaking creates a new action that makes an actor say a line of text. The dialog begins when
+// the action starts, so lines queued after other actions are said once the actor gets to them.
+func Speaking(app *App, text string, color Color) *Action {
+	var dialog Future
+	return &Action{
+		prom: NewPromise(),
+		f: func(a *Actor, done *Promise) {
+			if dialog == nil {
+				d := NewDialog(a, text, a.dialogPos(), color, 1.0)
+				app.BeginDialog(d)
+				dialog = d.Done()
+			}
+			a.drawCostume(CostumeSpeak(a.lookAt))
+			if dialog.IsCompleted() {
+				done.Complete()
+			}
 		},
 	}
 }
@@ -225,9 +686,7 @@ func SpeakingTo(dialog Future) *Action {
 	return &Action{
 		prom: NewPromise(),
 		f: func(a *Actor, done *Promise) {
-			if cos := a.costume; cos != nil {
-				cos.draw(CostumeSpeak(a.lookAt), a.costumePos())
-			}
+			a.drawCostume(CostumeSpeak(a.lookAt))
 			if dialog.IsCompleted() {
 				done.Complete()
 			}
@@ -269,5 +728,6 @@ func (a *App) SelectEgo(actor *Actor) {
 	a.ego = actor
 	if a.ego != nil {
 		a.ego.ego = true
+		a.camera.Follow(actor)
 	}
 }
@@ -0,0 +1,174 @@
+package pctk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActorEnqueue(t *testing.T) {
+	actor := pctk.NewActor("guybrush", "Guybrush")
+	actor.Do(pctk.Standing(pctk.DirRight))
+
+	first := actor.Enqueue(pctk.Turning(pctk.DirLeft))
+	second := actor.Enqueue(pctk.JumpingTo(pctk.NewPos(10, 20)))
+
+	// The idle action is replaced by the first queued action.
+	actor.Draw()
+	assert.True(t, first.IsCompleted())
+	assert.False(t, second.IsCompleted())
+	assert.Equal(t, pctk.DirLeft, actor.Direction())
+
+	actor.Draw()
+	assert.True(t, second.IsCompleted())
+	assert.Equal(t, pctk.NewPos(10, 20), actor.Position())
+}
+
+func TestActorDoCancelsQueuedActions(t *testing.T) {
+	actor := pctk.NewActor("guybrush", "Guybrush")
+	queued := actor.Enqueue(pctk.Turning(pctk.DirLeft))
+
+	actor.Do(pctk.Turning(pctk.DirUp))
+	_, err := queued.Wait()
+	assert.ErrorIs(t, err, pctk.PromiseBroken)
+
+	actor.Draw()
+	other := actor.Enqueue(pctk.Turning(pctk.DirDown))
+	actor.CancelAction()
+	_, err = other.Wait()
+	assert.ErrorIs(t, err, pctk.PromiseBroken)
+}
+
+func TestActorFollowingIsCancelledByQueuedActions(t *testing.T) {
+	guybrush := pctk.NewActor("guybrush", "Guybrush")
+	elaine := pctk.NewActor("elaine", "Elaine")
+
+	following := elaine.Do(pctk.Following(guybrush, 20, false))
+	elaine.Draw()
+	assert.False(t, following.IsCompleted())
+
+	queued := elaine.Enqueue(pctk.Turning(pctk.DirLeft))
+	elaine.Draw()
+	_, err := following.Wait()
+	assert.ErrorIs(t, err, pctk.PromiseBroken)
+	assert.True(t, queued.IsCompleted())
+}
+
+func TestActorRoutine(t *testing.T) {
+	actor := pctk.NewActor("guybrush", "Guybrush")
+	actor.SetRoutine(nil, []pctk.RoutineStep{
+		pctk.RoutineFace(pctk.DirLeft),
+		pctk.RoutineFace(pctk.DirUp),
+	})
+
+	actor.Draw()
+	assert.Equal(t, pctk.DirLeft, actor.Direction())
+	actor.Draw()
+	assert.Equal(t, pctk.DirUp, actor.Direction())
+	actor.Draw()
+	assert.Equal(t, pctk.DirLeft, actor.Direction())
+
+	actor.SetRoutine(nil, nil)
+	actor.Draw()
+	assert.Equal(t, pctk.DirLeft, actor.Direction())
+}
+
+func TestActorRoutineSkipsUnknownAnimations(t *testing.T) {
+	actor := pctk.NewActor("guybrush", "Guybrush")
+	actor.SetRoutine(nil, []pctk.RoutineStep{
+		pctk.RoutineAnimate("shrug"),
+		pctk.RoutineFace(pctk.DirUp),
+	})
+
+	// The actor has no costume, so the animation is skipped.
+	for i := 0; i < 3; i++ {
+		actor.Draw()
+	}
+	assert.Equal(t, pctk.DirUp, actor.Direction())
+}
+
+func TestActorRoutineIsResumedAfterInterruptions(t *testing.T) {
+	actor := pctk.NewActor("guybrush", "Guybrush")
+	actor.SetRoutine(nil, []pctk.RoutineStep{
+		pctk.RoutineWait(time.Hour),
+		pctk.RoutineFace(pctk.DirLeft),
+	})
+
+	actor.Draw()
+	interruption := actor.Do(pctk.Turning(pctk.DirUp))
+	actor.Draw()
+	assert.True(t, interruption.IsCompleted())
+	assert.Equal(t, pctk.DirUp, actor.Direction())
+
+	// The interrupted wait starts over, so the actor does not turn left yet.
+	actor.Draw()
+	actor.Draw()
+	assert.Equal(t, pctk.DirUp, actor.Direction())
+}
+
+func TestActorDetourAroundActorsAtCorners(t *testing.T) {
+	/*
+		Walk boxes disposition:
+
+		  +-------------------+---+
+		  |      street       |   |
+		  +-------------------+   |
+		                      |   |
+		                      | alley
+		                      |   |
+		                      +---+
+	*/
+	room := pctk.NewTestRoom(
+		pctk.NewWalkBox("street", []*pctk.Positionf{{0, 0}, {180, 0}, {180, 20}, {0, 20}}),
+		pctk.NewWalkBox("alley", []*pctk.Positionf{{180, 0}, {200, 0}, {200, 100}, {180, 100}}),
+	)
+	from, to := pctk.NewPos(20, 10), pctk.NewPos(190, 90)
+	path := room.FindPath(from, to)
+	assert.Equal(t, []pctk.Position{pctk.NewPos(180, 20), to}, path)
+
+	walker := pctk.NewActor("guybrush", "Guybrush")
+	walker.Footprint = pctk.NewSize(6, 4)
+	room.PutActor(walker)
+	walker.Locate(room, from, pctk.DirRight)
+
+	blocker := pctk.NewActor("pirate", "Pirate")
+	blocker.Footprint = pctk.NewSize(6, 4)
+	room.PutActor(blocker)
+	blocker.Locate(room, path[0], pctk.DirLeft)
+
+	// The actor goes round the pirate by the side of the corner that keeps it in the walkable area.
+	waypoint, ok := walker.Detour(path[0], path[1])
+	assert.True(t, ok)
+	assert.Equal(t, pctk.NewPos(187, 15), waypoint)
+
+	// There is no way round a pirate that blocks the whole corner, so the actor stops walking.
+	blocker.Footprint = pctk.NewSize(60, 60)
+	_, ok = walker.Detour(path[0], path[1])
+	assert.False(t, ok)
+
+	walking := walker.Do(pctk.WalkingPath(path))
+	walker.Draw()
+	assert.True(t, walking.IsCompleted())
+	assert.Equal(t, from, walker.Position())
+}
+
+func TestActorRoutineIsHeldByIdleActions(t *testing.T) {
+	guybrush := pctk.NewActor("guybrush", "Guybrush")
+	guard := pctk.NewActor("guard", "Guard")
+	guard.SetRoutine(nil, []pctk.RoutineStep{
+		pctk.RoutineFace(pctk.DirLeft),
+	})
+
+	following := guard.Do(pctk.Following(guybrush, 20, false))
+	guard.Draw()
+	guard.Draw()
+	assert.False(t, following.IsCompleted())
+	assert.NotEqual(t, pctk.DirLeft, guard.Direction())
+
+	// The routine goes on once the guard stops following.
+	guard.CancelAction()
+	guard.Draw()
+	assert.Equal(t, pctk.DirLeft, guard.Direction())
+}
@@ -83,22 +83,60 @@ func (a *Animation) BinaryDecode(r io.Reader) error {
 
 // Draw renders the animation in the viewport.
 func (a *Animation) Draw(sprites *SpriteSheet, pos Position) {
+	a.DrawScaled(sprites, pos, 1)
+}
+
+// DrawScaled renders the animation in the viewport, scaled by the given factor.
+func (a *Animation) DrawScaled(sprites *SpriteSheet, pos Position, scale float32) {
 	if a == nil {
 		return
 	}
-	if a.frames[a.currentFrame].delay < time.Since(a.lastFrame) {
-		a.lastFrame = time.Now()
-		a.currentFrame++
-		if a.currentFrame >= len(a.frames) {
-			a.currentFrame = 0
-		}
+	a.advance(true)
+	a.drawFrame(sprites, pos, scale)
+}
+
+// DrawOnce renders the animation in the viewport, scaled by the given factor, without looping. It
+// returns true when the last frame has been shown for its whole delay. Use Rewind to play it
+// again from the beginning.
+func (a *Animation) DrawOnce(sprites *SpriteSheet, pos Position, scale float32) bool {
+	if a == nil {
+		return true
+	}
+	done := a.advance(false)
+	a.drawFrame(sprites, pos, scale)
+	return done
+}
+
+// Rewind moves the animation back to its first frame.
+func (a *Animation) Rewind() {
+	a.currentFrame = 0
+	a.lastFrame = time.Now()
+}
+
+// advance moves the animation to the next frame if the delay of the current one has elapsed. If
+// loop is false, it returns true instead of moving from the last frame to the first one.
+func (a *Animation) advance(loop bool) bool {
+	if a.frames[a.currentFrame].delay >= time.Since(a.lastFrame) {
+		return false
 	}
+	if a.currentFrame+1 >= len(a.frames) && !loop {
+		return true
+	}
+	a.lastFrame = time.Now()
+	a.currentFrame++
+	if a.currentFrame >= len(a.frames) {
+		a.currentFrame = 0
+	}
+	return false
+}
 
-	sprites.DrawSprite(
+func (a *Animation) drawFrame(sprites *SpriteSheet, pos Position, scale float32) {
+	sprites.DrawSpriteScaled(
 		a.frames[a.currentFrame].col,
 		a.frames[a.currentFrame].row,
 		pos,
 		a.flip,
+		scale,
 	)
 }
 
@@ -11,6 +11,7 @@ type App struct {
 
 	screenCaption string
 	screenZoom    int32
+	runMode       RunMode
 
 	actors  map[string]*Actor
 	dialogs []Dialog
@@ -19,10 +20,13 @@ type App struct {
 	room    *Room
 	scripts map[ResourceRef]*Script
 
-	control  ControlPane
-	commands CommandQueue
+	control    ControlPane
+	commands   CommandQueue
+	debug      debugOverlay
+	transition *roomTransition
 
 	cam         rl.Camera2D
+	camera      Camera
 	cursorTx    rl.Texture2D
 	cursorColor Color
 	music       *Music
@@ -79,12 +83,21 @@ func (a *App) run() {
 	a.updateMusic()
 	rl.BeginDrawing()
 	rl.ClearBackground(rl.Black)
-	rl.BeginMode2D(a.cam)
+	a.camera.Update(a.room)
+	rl.BeginMode2D(a.camera.toRaylib(a.cam.Zoom))
+	rl.BeginScissorMode(0, 0, ScreenWidth*a.screenZoom, ViewportHeight*a.screenZoom)
 	a.drawSceneViewport()
+	a.debug.Draw(a)
+	rl.EndScissorMode()
+	rl.EndMode2D()
+	a.captureTransition()
+	rl.BeginMode2D(a.cam)
+	a.drawTransition()
 	a.control.Draw(a)
 	a.drawDialogs()
 	rl.EndMode2D()
 	rl.EndDrawing()
 	a.control.processControlInputs(a)
+	a.debug.processInputs()
 	a.commands.Execute(a)
 }
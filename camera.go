@@ -0,0 +1,172 @@
+package pctk
+
+import (
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+const (
+	// CameraDeadZoneWidth is the width of the area in the middle of the viewport where the actor
+	// followed by the camera can move without making the room scroll.
+	CameraDeadZoneWidth = 160
+
+	// CameraDeadZoneHeight is the height of the area in the middle of the viewport where the actor
+	// followed by the camera can move without making the room scroll.
+	CameraDeadZoneHeight = 72
+)
+
+var (
+	// DefaultCameraSpeed is the default speed of the camera when panning, in pixels per second on
+	// each axis.
+	DefaultCameraSpeed = NewPosf(160, 80)
+)
+
+// Camera is the part of the room that is shown in the viewport. Rooms larger than the viewport
+// scroll as the camera pans or follows an actor.
+type Camera struct {
+	pos    Positionf  // The top-left corner of the viewport in room coordinates
+	follow *Actor     // The actor followed by the camera, if any
+	pan    *cameraPan // The pan in progress, if any
+}
+
+// cameraPan is a movement of the camera towards a position or an actor.
+type cameraPan struct {
+	to    Positionf // The position to pan to, if not panning to an actor
+	actor *Actor    // The actor to pan to, if any
+	done  *Promise
+}
+
+// Position returns the top-left corner of the viewport in room coordinates.
+func (c *Camera) Position() Position {
+	return c.pos.ToPos()
+}
+
+// ToRoom converts a position in the viewport into room coordinates.
+func (c *Camera) ToRoom(pos Position) Position {
+	return pos.Add(c.Position())
+}
+
+// ToViewport converts a position in room coordinates into the viewport.
+func (c *Camera) ToViewport(pos Position) Position {
+	return pos.Sub(c.Position())
+}
+
+// Following returns the actor followed by the camera, or nil if none.
+func (c *Camera) Following() *Actor {
+	return c.follow
+}
+
+// SetPosition moves the camera immediately to the given position in room coordinates, stopping
+// any pan in progress and following no actor.
+func (c *Camera) SetPosition(pos Position) {
+	c.stop()
+	c.follow = nil
+	c.pos = pos.ToPosf()
+}
+
+// PanTo moves the camera smoothly to the given position in room coordinates, following no actor.
+// The returned future is completed when the camera arrives, or broken if interrupted by another
+// camera movement.
+func (c *Camera) PanTo(pos Position) Future {
+	c.stop()
+	c.follow = nil
+	c.pan = &cameraPan{to: pos.ToPosf(), done: NewPromise()}
+	return c.pan.done
+}
+
+// Follow makes the camera follow the given actor. The camera pans to the actor if it is out of the
+// dead zone, and the returned future is completed when the actor is into it. A nil actor stops
+// following.
+func (c *Camera) Follow(actor *Actor) Future {
+	c.stop()
+	c.follow = actor
+	if actor == nil {
+		prom := NewPromise()
+		prom.Complete()
+		return prom
+	}
+	c.pan = &cameraPan{actor: actor, done: NewPromise()}
+	return c.pan.done
+}
+
+// Reset places the camera when the given room is shown. It looks at the followed actor if it is in
+// the room, or the top-left corner of the room otherwise.
+func (c *Camera) Reset(room *Room) {
+	c.stop()
+	c.pos = Positionf{}
+	if c.follow != nil && c.follow.room == room {
+		c.pos = c.track(c.follow.pos)
+	}
+	if room != nil {
+		c.pos = c.clamp(room, c.pos)
+	}
+}
+
+// Update moves the camera for the current frame in the given room.
+func (c *Camera) Update(room *Room) {
+	if room == nil {
+		return
+	}
+	if pan := c.pan; pan != nil {
+		to := pan.to
+		if pan.actor != nil {
+			if pan.actor.room != room {
+				// Nothing to pan to until the actor enters the room.
+				c.pan = nil
+				pan.done.Complete()
+				return
+			}
+			to = c.track(pan.actor.pos)
+		}
+		to = c.clamp(room, to)
+		c.pos = c.pos.Move(to, DefaultCameraSpeed.Scale(rl.GetFrameTime()))
+		if c.pos == to {
+			c.pan = nil
+			pan.done.Complete()
+		}
+	} else if c.follow != nil && c.follow.room == room {
+		c.pos = c.track(c.follow.pos)
+	}
+	c.pos = c.clamp(room, c.pos)
+}
+
+// toRaylib returns the raylib camera to draw the room with the given zoom.
+func (c *Camera) toRaylib(zoom float32) rl.Camera2D {
+	return rl.Camera2D{
+		Target: c.Position().toRaylib(),
+		Zoom:   zoom,
+	}
+}
+
+// track returns the position of the camera closest to the current one that keeps the given
+// position into the dead zone.
+func (c *Camera) track(pos Positionf) Positionf {
+	cam := c.pos
+	left := cam.X + (ScreenWidth-CameraDeadZoneWidth)/2
+	top := cam.Y + (ViewportHeight-CameraDeadZoneHeight)/2
+	if pos.X < left {
+		cam.X -= left - pos.X
+	} else if pos.X > left+CameraDeadZoneWidth {
+		cam.X += pos.X - left - CameraDeadZoneWidth
+	}
+	if pos.Y < top {
+		cam.Y -= top - pos.Y
+	} else if pos.Y > top+CameraDeadZoneHeight {
+		cam.Y += pos.Y - top - CameraDeadZoneHeight
+	}
+	return cam
+}
+
+// clamp returns the given camera position limited to keep the viewport into the room.
+func (c *Camera) clamp(room *Room, pos Positionf) Positionf {
+	size := room.Size()
+	pos.X = max(0, min(pos.X, float32(size.W-ScreenWidth)))
+	pos.Y = max(0, min(pos.Y, float32(size.H-ViewportHeight)))
+	return pos
+}
+
+func (c *Camera) stop() {
+	if c.pan != nil {
+		c.pan.done.Break()
+		c.pan = nil
+	}
+}
@@ -0,0 +1,36 @@
+package pctk_test
+
+import (
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCameraCoordinates(t *testing.T) {
+	var cam pctk.Camera
+	cam.SetPosition(pctk.NewPos(20, 10))
+
+	assert.Equal(t, pctk.NewPos(20, 10), cam.Position())
+	assert.Equal(t, pctk.NewPos(120, 60), cam.ToRoom(pctk.NewPos(100, 50)))
+	assert.Equal(t, pctk.NewPos(100, 50), cam.ToViewport(pctk.NewPos(120, 60)))
+}
+
+func TestCameraMovementsInterruptPans(t *testing.T) {
+	var cam pctk.Camera
+	actor := pctk.NewActor("guybrush", "Guybrush")
+
+	pan := cam.PanTo(pctk.NewPos(100, 0))
+	follow := cam.Follow(actor)
+	assert.True(t, pan.IsCompleted())
+	_, err := pan.Wait()
+	assert.ErrorIs(t, err, pctk.PromiseBroken)
+	assert.Equal(t, actor, cam.Following())
+
+	cam.SetPosition(pctk.NewPos(0, 0))
+	_, err = follow.Wait()
+	assert.ErrorIs(t, err, pctk.PromiseBroken)
+	assert.Nil(t, cam.Following())
+
+	assert.True(t, cam.Follow(nil).IsCompleted())
+}
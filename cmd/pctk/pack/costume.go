@@ -40,6 +40,11 @@ func (d *CostumeData) UnmarshalYAML(n *yaml.Node) error {
 				Duration int
 			}
 		}
+		Fidgets struct {
+			Delay    int
+			Interval int
+			Actions  []string
+		}
 	}
 	if err := n.Decode(&data); err != nil {
 		return err
@@ -51,6 +56,10 @@ func (d *CostumeData) UnmarshalYAML(n *yaml.Node) error {
 	)
 	d.Resource = pctk.NewCostume(sprites)
 
+	// Custom actions given by name are assigned codes once the numeric ones are known.
+	named := make(map[string]*pctk.Animation)
+	var names []string
+
 	for _, anim := range data.Animations {
 		dir := func() pctk.Direction {
 			switch strings.ToLower(anim.Dir) {
@@ -62,6 +71,14 @@ func (d *CostumeData) UnmarshalYAML(n *yaml.Node) error {
 				return pctk.DirUp
 			case "down":
 				return pctk.DirDown
+			case "upright":
+				return pctk.DirUpRight
+			case "downright":
+				return pctk.DirDownRight
+			case "downleft":
+				return pctk.DirDownLeft
+			case "upleft":
+				return pctk.DirUpLeft
 			default:
 				panic(fmt.Sprintf("invalid direction %q", anim.Dir))
 			}
@@ -84,16 +101,59 @@ func (d *CostumeData) UnmarshalYAML(n *yaml.Node) error {
 			act = pctk.CostumeSpeak(dir())
 		case "walk":
 			act = pctk.CostumeWalk(dir())
+		case "turn":
+			act = pctk.CostumeTurn(dir())
+		case "":
+			return fmt.Errorf("missing action in animation")
 		default:
 			code, err := strconv.Atoi(anim.Action)
 			if err != nil {
-				err := fmt.Errorf("neither a default action nor a custom action code: %w", err)
-				return fmt.Errorf("invalid action %q: %w", anim.Action, err)
+				// Not a number, so it is the name of a custom action.
+				if _, ok := named[anim.Action]; ok {
+					return fmt.Errorf("duplicated action %q", anim.Action)
+				}
+				named[anim.Action] = a
+				names = append(names, anim.Action)
+				continue
+			}
+			if code < 0 || code > 0xFF {
+				return fmt.Errorf("invalid action %q: action codes range from 0 to 255", anim.Action)
 			}
 			act = pctk.CostumeAction(code)
 		}
 		d.Resource.WithAnimation(act, a)
 	}
 
+	for _, name := range names {
+		act, ok := d.Resource.NextCustomAction()
+		if !ok {
+			return fmt.Errorf("too many custom actions, cannot assign a code to %q", name)
+		}
+		d.Resource.WithCustomAction(name, act).WithAnimation(act, named[name])
+	}
+
+	var fidgets []pctk.CostumeAction
+	for _, name := range data.Fidgets.Actions {
+		act, ok := d.Resource.CustomAction(name)
+		if !ok {
+			code, err := strconv.Atoi(name)
+			if err != nil || code < 0 || code > 0xFF {
+				return fmt.Errorf("invalid fidget %q: unknown action", name)
+			}
+			act = pctk.CostumeAction(code)
+		}
+		if !d.Resource.HasAnimation(act) {
+			return fmt.Errorf("invalid fidget %q: no animation for the action", name)
+		}
+		fidgets = append(fidgets, act)
+	}
+	if len(fidgets) > 0 {
+		d.Resource.WithFidgets(
+			time.Duration(data.Fidgets.Delay)*time.Millisecond,
+			time.Duration(data.Fidgets.Interval)*time.Millisecond,
+			fidgets...,
+		)
+	}
+
 	return nil
 }
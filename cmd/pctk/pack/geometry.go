@@ -0,0 +1,36 @@
+package pack
+
+import (
+	"path/filepath"
+
+	"github.com/apoloval/pctk"
+	"gopkg.in/yaml.v3"
+)
+
+// RoomGeometryData is the data for a room geometry resource.
+type RoomGeometryData struct {
+	Resource *pctk.RoomGeometry
+
+	workingDir string
+}
+
+// NewRoomGeometryData creates a new room geometry data associated with a working directory.
+func NewRoomGeometryData(workingDir string) *RoomGeometryData {
+	return &RoomGeometryData{workingDir: workingDir}
+}
+
+func (d *RoomGeometryData) UnmarshalYAML(n *yaml.Node) error {
+	var data struct {
+		Source string
+	}
+	if err := n.Decode(&data); err != nil {
+		return err
+	}
+
+	m, err := loadTiledMap(filepath.Join(d.workingDir, data.Source))
+	if err != nil {
+		return err
+	}
+	d.Resource, err = m.RoomGeometry()
+	return err
+}
@@ -23,6 +23,9 @@ const (
 	// ManifestTypeMusic is a music resource.
 	ManifestTypeMusic ResourceType = "music"
 
+	// ManifestTypeRoomGeometry is a room geometry resource imported from a Tiled map.
+	ManifestTypeRoomGeometry ResourceType = "roomgeometry"
+
 	// ManifestTypeScript is a script resource.
 	ManifestTypeScript ResourceType = "script"
 
@@ -75,6 +78,8 @@ func (m *Manifest) UnmarshalYAML(n *yaml.Node) error {
 		m.Data = NewMusicData(m.workingDir)
 	case ManifestTypeImage:
 		m.Data = NewImageData(m.workingDir)
+	case ManifestTypeRoomGeometry:
+		m.Data = NewRoomGeometryData(m.workingDir)
 	case ManifestTypeScript:
 		m.Data = new(ScriptData)
 	case ManifestTypeSound:
@@ -30,6 +30,7 @@ func do(src string, output string) error {
 		return err
 	}
 	for _, manifest := range manifests {
+		var warnings []error
 		id := pctk.ResourceID(strings.TrimSuffix(manifest, filepath.Ext(manifest)))
 		fmt.Printf("Packing %s...", id)
 		man, err := LoadManifestFromFile(filepath.Join(src, manifest))
@@ -44,6 +45,9 @@ func do(src string, output string) error {
 			err = enc.EncodeImage(id, data.Resource, man.Compression)
 		case *MusicData:
 			err = enc.EncodeMusic(id, data.Resource, man.Compression)
+		case *RoomGeometryData:
+			err = enc.EncodeRoomGeometry(id, data.Resource, man.Compression)
+			warnings = data.Resource.Validate()
 		case *ScriptData:
 			err = enc.EncodeScript(id, data.Resource, man.Compression)
 		case *SoundData:
@@ -56,6 +60,9 @@ func do(src string, output string) error {
 			return err
 		}
 		fmt.Printf(" Done\n")
+		for _, warn := range warnings {
+			fmt.Printf("  Warning: %s: %v\n", id, warn)
+		}
 	}
 
 	luaScripts, err := listLuaScripts(src)
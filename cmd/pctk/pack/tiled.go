@@ -0,0 +1,318 @@
+package pack
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/apoloval/pctk"
+)
+
+// tiledMap is a map file created with the Tiled map editor. Only the object layers are relevant to
+// import the geometry of the rooms. Both JSON (.json, .tmj) and XML (.tmx) formats are supported.
+type tiledMap struct {
+	Layers []tiledLayer `json:"layers"`
+}
+
+type tiledLayer struct {
+	Name    string        `json:"name"`
+	Objects []tiledObject `json:"objects"`
+	Layers  []tiledLayer  `json:"layers"`
+}
+
+type tiledObject struct {
+	ID         int             `json:"id" xml:"id,attr"`
+	Name       string          `json:"name" xml:"name,attr"`
+	X          float32         `json:"x" xml:"x,attr"`
+	Y          float32         `json:"y" xml:"y,attr"`
+	Width      float32         `json:"width" xml:"width,attr"`
+	Height     float32         `json:"height" xml:"height,attr"`
+	Rotation   float32         `json:"rotation" xml:"rotation,attr"`
+	Point      bool            `json:"point" xml:"-"`
+	Ellipse    bool            `json:"ellipse" xml:"-"`
+	Polygon    []tiledPoint    `json:"polygon" xml:"-"`
+	Polyline   []tiledPoint    `json:"polyline" xml:"-"`
+	Properties []tiledProperty `json:"properties" xml:"-"`
+}
+
+type tiledPoint struct {
+	X float32 `json:"x"`
+	Y float32 `json:"y"`
+}
+
+type tiledProperty struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+// loadTiledMap loads a Tiled map from the given file.
+func loadTiledMap(path string) (*tiledMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var m tiledMap
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tmx", ".xml":
+		err = m.unmarshalXML(data)
+	default:
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid Tiled map %s: %w", path, err)
+	}
+	return &m, nil
+}
+
+// unmarshalXML decodes the map from the TMX format. The XML layout differs from the JSON one in
+// the way shapes and properties are represented, so they are converted to the JSON model.
+func (m *tiledMap) unmarshalXML(data []byte) error {
+	type xmlObject struct {
+		tiledObject
+		Point   *struct{} `xml:"point"`
+		Ellipse *struct{} `xml:"ellipse"`
+		Polygon *struct {
+			Points string `xml:"points,attr"`
+		} `xml:"polygon"`
+		Polyline *struct {
+			Points string `xml:"points,attr"`
+		} `xml:"polyline"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"properties>property"`
+	}
+	type xmlLayer struct {
+		Name    string      `xml:"name,attr"`
+		Objects []xmlObject `xml:"object"`
+		Groups  []xmlLayer  `xml:"group"`
+		Layers  []xmlLayer  `xml:"objectgroup"`
+	}
+	var doc struct {
+		Groups []xmlLayer `xml:"group"`
+		Layers []xmlLayer `xml:"objectgroup"`
+	}
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	var convert func(layers []xmlLayer) ([]tiledLayer, error)
+	convert = func(layers []xmlLayer) ([]tiledLayer, error) {
+		var result []tiledLayer
+		for _, layer := range layers {
+			converted := tiledLayer{Name: layer.Name}
+			for _, obj := range layer.Objects {
+				o := obj.tiledObject
+				o.Point = obj.Point != nil
+				o.Ellipse = obj.Ellipse != nil
+				if obj.Polygon != nil {
+					points, err := parseTiledPoints(obj.Polygon.Points)
+					if err != nil {
+						return nil, err
+					}
+					o.Polygon = points
+				}
+				if obj.Polyline != nil {
+					points, err := parseTiledPoints(obj.Polyline.Points)
+					if err != nil {
+						return nil, err
+					}
+					o.Polyline = points
+				}
+				for _, prop := range obj.Properties {
+					o.Properties = append(o.Properties, tiledProperty{Name: prop.Name, Value: prop.Value})
+				}
+				converted.Objects = append(converted.Objects, o)
+			}
+			children, err := convert(append(layer.Layers, layer.Groups...))
+			if err != nil {
+				return nil, err
+			}
+			converted.Layers = children
+			result = append(result, converted)
+		}
+		return result, nil
+	}
+
+	layers, err := convert(append(doc.Layers, doc.Groups...))
+	m.Layers = layers
+	return err
+}
+
+// RoomGeometry returns the room geometry described by the objects of the map. Polygons are
+// imported as walk boxes, rectangles as object hotspots and points as object use positions. The
+// name of each Tiled object is used as walk box or object ID.
+//
+// Walk boxes accept the following custom properties: scale (or scaletop and scalebottom), speed,
+// enabled and zplanes (a comma-separated list of z-plane IDs).
+func (m *tiledMap) RoomGeometry() (*pctk.RoomGeometry, error) {
+	geom := pctk.NewRoomGeometry()
+	err := m.forEachObject(m.Layers, func(layer string, obj tiledObject) error {
+		if obj.Name == "" {
+			return fmt.Errorf("object %d in layer %q has no name", obj.ID, layer)
+		}
+		if obj.Rotation != 0 {
+			return fmt.Errorf("object %q is rotated, which is not supported", obj.Name)
+		}
+		switch {
+		case len(obj.Polygon) > 0:
+			walkbox, err := obj.walkBox()
+			if err != nil {
+				return err
+			}
+			geom.WalkBoxes = append(geom.WalkBoxes, walkbox)
+		case obj.Point:
+			geom.UsePositions[obj.Name] = pctk.NewPos(int(obj.X), int(obj.Y))
+		case obj.Ellipse || len(obj.Polyline) > 0:
+			return fmt.Errorf("object %q has an unsupported shape", obj.Name)
+		default:
+			geom.Hotspots[obj.Name] = pctk.NewRect(
+				int(obj.X), int(obj.Y), int(obj.Width), int(obj.Height),
+			)
+		}
+		return nil
+	})
+	return geom, err
+}
+
+func (m *tiledMap) forEachObject(layers []tiledLayer, f func(string, tiledObject) error) error {
+	for _, layer := range layers {
+		for _, obj := range layer.Objects {
+			if err := f(layer.Name, obj); err != nil {
+				return err
+			}
+		}
+		if err := m.forEachObject(layer.Layers, f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o tiledObject) walkBox() (*pctk.WalkBox, error) {
+	vertices := make([]*pctk.Positionf, len(o.Polygon))
+	for i, p := range o.Polygon {
+		vertices[i] = &pctk.Positionf{X: o.X + p.X, Y: o.Y + p.Y}
+	}
+
+	if err := pctk.ValidateWalkBox(o.Name, vertices); err != nil {
+		return nil, err
+	}
+	walkbox := pctk.NewWalkBox(o.Name, vertices)
+
+	scale, err := o.floatProperty("scale", 1)
+	if err != nil {
+		return nil, err
+	}
+	top, err := o.floatProperty("scaletop", scale)
+	if err != nil {
+		return nil, err
+	}
+	bottom, err := o.floatProperty("scalebottom", scale)
+	if err != nil {
+		return nil, err
+	}
+	speed, err := o.floatProperty("speed", 1)
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := o.boolProperty("enabled", true)
+	if err != nil {
+		return nil, err
+	}
+	zplanes, err := o.listProperty("zplanes")
+	if err != nil {
+		return nil, err
+	}
+	return walkbox.WithScale(top, bottom).WithSpeed(speed).WithEnabled(enabled).WithZPlanes(zplanes...), nil
+}
+
+func (o tiledObject) property(name string) (any, bool) {
+	for _, prop := range o.Properties {
+		if strings.EqualFold(prop.Name, name) {
+			return prop.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o tiledObject) floatProperty(name string, def float32) (float32, error) {
+	val, ok := o.property(name)
+	if !ok {
+		return def, nil
+	}
+	switch v := val.(type) {
+	case float64:
+		return float32(v), nil
+	case string:
+		f, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid property %q of object %q: %w", name, o.Name, err)
+		}
+		return float32(f), nil
+	default:
+		return 0, fmt.Errorf("invalid property %q of object %q: number expected", name, o.Name)
+	}
+}
+
+func (o tiledObject) boolProperty(name string, def bool) (bool, error) {
+	val, ok := o.property(name)
+	if !ok {
+		return def, nil
+	}
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+	case string:
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("invalid property %q of object %q: %w", name, o.Name, err)
+		}
+		return b, nil
+	default:
+		return false, fmt.Errorf("invalid property %q of object %q: boolean expected", name, o.Name)
+	}
+}
+
+func (o tiledObject) listProperty(name string) ([]string, error) {
+	val, ok := o.property(name)
+	if !ok {
+		return nil, nil
+	}
+	s, ok := val.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid property %q of object %q: string expected", name, o.Name)
+	}
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func parseTiledPoints(s string) ([]tiledPoint, error) {
+	var points []tiledPoint
+	for _, pair := range strings.Fields(s) {
+		coords := strings.Split(pair, ",")
+		if len(coords) != 2 {
+			return nil, fmt.Errorf("invalid point %q", pair)
+		}
+		x, err := strconv.ParseFloat(coords[0], 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %w", pair, err)
+		}
+		y, err := strconv.ParseFloat(coords[1], 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid point %q: %w", pair, err)
+		}
+		points = append(points, tiledPoint{float32(x), float32(y)})
+	}
+	return points, nil
+}
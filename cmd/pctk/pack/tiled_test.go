@@ -0,0 +1,175 @@
+package pack_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/apoloval/pctk/cmd/pctk/pack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const tiledJSONMap = `{
+	"layers": [
+		{
+			"name": "walkboxes",
+			"objects": [
+				{
+					"id": 1, "name": "street", "x": 0, "y": 112,
+					"polygon": [{"x": 0, "y": 0}, {"x": 479, "y": 0}, {"x": 479, "y": 31}, {"x": 0, "y": 31}]
+				},
+				{
+					"id": 2, "name": "alley", "x": 110, "y": 92,
+					"polygon": [{"x": 30, "y": 0}, {"x": 90, "y": 0}, {"x": 120, "y": 20}, {"x": 0, "y": 20}],
+					"properties": [
+						{"name": "scaletop", "type": "float", "value": 0.5},
+						{"name": "enabled", "type": "bool", "value": false},
+						{"name": "zplanes", "type": "string", "value": "lamp, fence"}
+					]
+				}
+			]
+		},
+		{
+			"name": "props",
+			"layers": [
+				{
+					"name": "objects",
+					"objects": [
+						{"id": 3, "name": "door", "x": 10, "y": 20, "width": 30, "height": 60},
+						{"id": 4, "name": "door", "x": 25, "y": 85, "point": true}
+					]
+				}
+			]
+		}
+	]
+}`
+
+const tiledTMXMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="30" height="9" tilewidth="16" tileheight="16">
+	<objectgroup id="1" name="walkboxes">
+		<object id="1" name="street" x="0" y="112">
+			<polygon points="0,0 479,0 479,31 0,31"/>
+		</object>
+		<object id="2" name="alley" x="110" y="92">
+			<properties>
+				<property name="scaletop" type="float" value="0.5"/>
+				<property name="enabled" type="bool" value="false"/>
+				<property name="zplanes" value="lamp, fence"/>
+			</properties>
+			<polygon points="30,0 90,0 120,20 0,20"/>
+		</object>
+	</objectgroup>
+	<group id="2" name="props">
+		<objectgroup id="3" name="objects">
+			<object id="3" name="door" x="10" y="20" width="30" height="60"/>
+			<object id="4" name="door" x="25" y="85">
+				<point/>
+			</object>
+		</objectgroup>
+	</group>
+</map>`
+
+func loadTestRoomGeometry(t *testing.T, file, content string) (*pctk.RoomGeometry, error) {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+
+	data := pack.NewRoomGeometryData(dir)
+	err := yaml.Unmarshal([]byte("source: "+file), data)
+	return data.Resource, err
+}
+
+func TestRoomGeometryFromTiledMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "JSON", file: "dock.tmj", content: tiledJSONMap},
+		{name: "TMX", file: "dock.tmx", content: tiledTMXMap},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			geom, err := loadTestRoomGeometry(t, tt.file, tt.content)
+			require.NoError(t, err)
+
+			require.Len(t, geom.WalkBoxes, 2)
+			street, alley := geom.WalkBoxes[0], geom.WalkBoxes[1]
+			assert.Equal(t, "street", street.ID())
+			assert.Equal(t, []*pctk.Positionf{
+				{X: 0, Y: 112}, {X: 479, Y: 112}, {X: 479, Y: 143}, {X: 0, Y: 143},
+			}, street.Vertices())
+			assert.True(t, street.IsEnabled())
+			assert.Equal(t, float32(1), street.ScaleAt(112))
+			assert.Empty(t, street.ZPlanes())
+
+			assert.Equal(t, "alley", alley.ID())
+			assert.Equal(t, []*pctk.Positionf{
+				{X: 140, Y: 92}, {X: 200, Y: 92}, {X: 230, Y: 112}, {X: 110, Y: 112},
+			}, alley.Vertices())
+			assert.False(t, alley.IsEnabled())
+			assert.Equal(t, float32(0.5), alley.ScaleAt(92))
+			assert.Equal(t, float32(1), alley.ScaleAt(112))
+			assert.Equal(t, []string{"lamp", "fence"}, alley.ZPlanes())
+
+			assert.Equal(t, map[string]pctk.Rectangle{"door": pctk.NewRect(10, 20, 30, 60)}, geom.Hotspots)
+			assert.Equal(t, map[string]pctk.Position{"door": pctk.NewPos(25, 85)}, geom.UsePositions)
+		})
+	}
+}
+
+func TestRoomGeometryFromTiledMapErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		err     string
+	}{
+		{
+			name:    "JSON unnamed object",
+			file:    "dock.json",
+			content: `{"layers": [{"name": "objects", "objects": [{"id": 7, "x": 10, "y": 20, "width": 30, "height": 60}]}]}`,
+			err:     "object 7 in layer \"objects\" has no name",
+		},
+		{
+			name: "JSON concave walk box",
+			file: "dock.json",
+			content: `{"layers": [{"name": "walkboxes", "objects": [{"id": 1, "name": "street", "x": 0, "y": 0,
+				"polygon": [{"x": 0, "y": 0}, {"x": 40, "y": 0}, {"x": 20, "y": 10}, {"x": 40, "y": 20}, {"x": 0, "y": 20}]}]}]}`,
+			err: "convex",
+		},
+		{
+			name:    "JSON invalid property",
+			file:    "dock.json",
+			content: `{"layers": [{"name": "walkboxes", "objects": [{"id": 1, "name": "street", "x": 0, "y": 0, "polygon": [{"x": 0, "y": 0}, {"x": 40, "y": 0}, {"x": 40, "y": 20}], "properties": [{"name": "speed", "type": "bool", "value": true}]}]}]}`,
+			err:     "invalid property \"speed\" of object \"street\"",
+		},
+		{
+			name:    "TMX ellipse",
+			file:    "dock.tmx",
+			content: `<map><objectgroup name="objects"><object id="1" name="barrel" x="10" y="20" width="30" height="30"><ellipse/></object></objectgroup></map>`,
+			err:     "object \"barrel\" has an unsupported shape",
+		},
+		{
+			name:    "TMX rotated object",
+			file:    "dock.tmx",
+			content: `<map><objectgroup name="objects"><object id="1" name="door" x="10" y="20" width="30" height="60" rotation="45"/></objectgroup></map>`,
+			err:     "object \"door\" is rotated",
+		},
+		{
+			name:    "TMX invalid points",
+			file:    "dock.tmx",
+			content: `<map><objectgroup name="walkboxes"><object id="1" name="street" x="0" y="0"><polygon points="0,0 40 40,20"/></object></objectgroup></map>`,
+			err:     "invalid point \"40\"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadTestRoomGeometry(t, tt.file, tt.content)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package pctk
+
+import "slices"
+
+// footprintMargin is the distance kept between actors when walking around each other.
+const footprintMargin = 1
+
+// obstacle is an area of the room an actor cannot walk through. It is the footprint of a blocking
+// actor expanded by the footprint of the walking actor, so the latter can be handled as a point.
+type obstacle struct {
+	min, max Positionf
+}
+
+// contains returns true if p is strictly inside the obstacle.
+func (o obstacle) contains(p Positionf) bool {
+	return p.X > o.min.X && p.X < o.max.X && p.Y > o.min.Y && p.Y < o.max.Y
+}
+
+// blocks returns true if the segment from p1 to p2 crosses the interior of the obstacle.
+func (o obstacle) blocks(p1, p2 Positionf) bool {
+	// Liang-Barsky clipping of the segment against the obstacle.
+	t0, t1 := float32(0), float32(1)
+	d := p2.Sub(p1)
+	clip := func(p, q float32) bool {
+		if p == 0 {
+			return q > 0
+		}
+		t := q / p
+		if p < 0 {
+			t0 = max(t0, t)
+		} else {
+			t1 = min(t1, t)
+		}
+		return t0 < t1
+	}
+	if !clip(-d.X, p1.X-o.min.X) || !clip(d.X, o.max.X-p1.X) ||
+		!clip(-d.Y, p1.Y-o.min.Y) || !clip(d.Y, o.max.Y-p1.Y) {
+		return false
+	}
+	// The segment overlaps the obstacle. Check that it goes through its interior, and not only
+	// along its border.
+	middle := p1.Add(d.Scale((t0 + t1) / 2))
+	return o.contains(middle)
+}
+
+// corners returns the corners of the obstacle, slightly moved outwards.
+func (o obstacle) corners() []Positionf {
+	minX, minY := o.min.X-footprintMargin, o.min.Y-footprintMargin
+	maxX, maxY := o.max.X+footprintMargin, o.max.Y+footprintMargin
+	return []Positionf{{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}}
+}
+
+// closestFreePoint returns the closest walkable point to p that is out of the obstacle. If there
+// is no such point, it returns false.
+func (o obstacle) closestFreePoint(p Positionf, walkable func(Positionf) bool) (Positionf, bool) {
+	if !o.contains(p) {
+		return p, true
+	}
+	candidates := []Positionf{
+		{o.min.X - footprintMargin, p.Y},
+		{o.max.X + footprintMargin, p.Y},
+		{p.X, o.min.Y - footprintMargin},
+		{p.X, o.max.Y + footprintMargin},
+	}
+	var closest Positionf
+	found := false
+	for _, c := range candidates {
+		if walkable(c) && (!found || p.DistanceTo(&c) < p.DistanceTo(&closest)) {
+			closest, found = c, true
+		}
+	}
+	return closest, found
+}
+
+// obstacles returns the areas of the room that the actor cannot walk through because of the
+// footprints of other actors.
+func (a *Actor) obstacles() []obstacle {
+	if a.room == nil {
+		return nil
+	}
+	var result []obstacle
+	for _, other := range a.room.actors {
+		if other == a || other.Footprint == (Size{}) {
+			continue
+		}
+		half := NewPosf(
+			float32(other.Footprint.W+a.Footprint.W)/2,
+			float32(other.Footprint.H+a.Footprint.H)/2,
+		)
+		result = append(result, obstacle{min: other.pos.Sub(half), max: other.pos.Add(half)})
+	}
+	return result
+}
+
+// walkable returns true if the given position is in the walkable area of the actor's room.
+func (a *Actor) walkable(p Positionf) bool {
+	return a.room.IsWalkable(p.ToPos())
+}
+
+// freeDestination returns the closest walkable position to dest that is not occupied by the
+// given obstacles. If there is no such position, it returns false.
+func freeDestination(
+	dest Positionf,
+	obstacles []obstacle,
+	walkable func(Positionf) bool,
+) (Positionf, bool) {
+	// Moving out of one obstacle might move into another one. Give up after trying them all.
+	for range obstacles {
+		moved := false
+		for _, o := range obstacles {
+			if !o.contains(dest) {
+				continue
+			}
+			free, ok := o.closestFreePoint(dest, walkable)
+			if !ok {
+				return dest, false
+			}
+			dest, moved = free, true
+		}
+		if !moved {
+			return dest, true
+		}
+	}
+	for _, o := range obstacles {
+		if o.contains(dest) {
+			return dest, false
+		}
+	}
+	return dest, true
+}
+
+// detour returns the point to walk through instead of a waypoint that is covered by the given
+// obstacles, on the way from 'from' to 'next'. Waypoints are usually corners of the walkable area,
+// so the point is chosen among the corners of the obstacles so that both the way to it and the way
+// from it to 'next' are in the walkable area. If there is no such point, it returns false.
+func detour(
+	from, waypoint, next Positionf,
+	obstacles []obstacle,
+	walkable func(Positionf) bool,
+) (Positionf, bool) {
+	var best Positionf
+	found := false
+	for _, o := range obstacles {
+		if !o.contains(waypoint) {
+			continue
+		}
+		for _, c := range o.corners() {
+			if slices.ContainsFunc(obstacles, func(o obstacle) bool { return o.contains(c) }) ||
+				!walkableSegment(from, c, walkable) || !walkableSegment(c, next, walkable) {
+				continue
+			}
+			length := from.DistanceTo(&c) + c.DistanceTo(&next)
+			if !found || length < from.DistanceTo(&best)+best.DistanceTo(&next) {
+				best, found = c, true
+			}
+		}
+	}
+	return best, found
+}
+
+// walkableSegment returns true if the points of the segment from p1 to p2 are walkable, checked
+// every pixel.
+func walkableSegment(p1, p2 Positionf, walkable func(Positionf) bool) bool {
+	d := p2.Sub(p1)
+	steps := int(p1.DistanceTo(&p2)) + 1
+	for i := 0; i <= steps; i++ {
+		if !walkable(p1.Add(d.Scale(float32(i) / float32(steps)))) {
+			return false
+		}
+	}
+	return true
+}
+
+// steer returns the position to walk towards in the way from 'from' to 'to' avoiding the given
+// obstacles. If the straight line is free, 'to' is returned. Otherwise, it returns the first
+// corner of the shortest way around the obstacles. Obstacles containing 'from' are ignored, so
+// actors that overlap can walk away from each other. The walkable function is used to discard
+// corners out of the walkable area. If there is no way to go around the obstacles, it returns false.
+func steer(
+	from, to Positionf,
+	obstacles []obstacle,
+	walkable func(Positionf) bool,
+) (Positionf, bool) {
+	var active []obstacle
+	for _, o := range obstacles {
+		if !o.contains(from) {
+			active = append(active, o)
+		}
+	}
+	visible := func(p1, p2 Positionf) bool {
+		for _, o := range active {
+			if o.blocks(p1, p2) {
+				return false
+			}
+		}
+		return true
+	}
+	if visible(from, to) {
+		return to, true
+	}
+
+	// Compute the shortest path in the visibility graph formed by the origin, the destination and
+	// the corners of the obstacles. The corner the actor is already at is discarded, so it goes
+	// on to the next one.
+	nodes := []Positionf{from, to}
+	for _, o := range active {
+		for _, corner := range o.corners() {
+			if from.DistanceTo(&corner) < footprintMargin || !walkable(corner) {
+				continue
+			}
+			if !slices.ContainsFunc(active, func(o obstacle) bool { return o.contains(corner) }) {
+				nodes = append(nodes, corner)
+			}
+		}
+	}
+	dist := make([]float32, len(nodes))
+	prev := make([]int, len(nodes))
+	done := make([]bool, len(nodes))
+	for i := range nodes {
+		dist[i], prev[i] = infinityDistance, -1
+	}
+	dist[0] = 0
+	for {
+		current := -1
+		for i := range nodes {
+			if !done[i] && dist[i] < infinityDistance && (current < 0 || dist[i] < dist[current]) {
+				current = i
+			}
+		}
+		if current < 0 || current == 1 {
+			break
+		}
+		done[current] = true
+		for i := range nodes {
+			if done[i] || !visible(nodes[current], nodes[i]) {
+				continue
+			}
+			if d := dist[current] + nodes[current].DistanceTo(&nodes[i]); d < dist[i] {
+				dist[i], prev[i] = d, current
+			}
+		}
+	}
+	if prev[1] < 0 {
+		return from, false
+	}
+
+	next := 1
+	for prev[next] != 0 {
+		next = prev[next]
+	}
+	return nodes[next], true
+}
@@ -2,6 +2,7 @@ package pctk
 
 import (
 	"log"
+	"slices"
 	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
@@ -12,9 +13,12 @@ type ActorDeclare struct {
 	ActorID   string
 	ActorName string
 	Costume   ResourceRef
+	Footprint Size
+	Routine   []RoutineStep
 	TalkColor Color
 	ScriptLoc FieldAccessor
 	Size      Size
+	Speed     Positionf
 	UsePos    Position
 	UseDir    Direction
 }
@@ -22,16 +26,49 @@ type ActorDeclare struct {
 func (cmd ActorDeclare) Execute(app *App, done *Promise) {
 	actor := app.DeclareActor(cmd.ActorID, cmd.ActorName)
 	if cmd.Costume != ResourceRefNull {
-		actor.SetCostume(app.res.LoadCostume(cmd.Costume))
+		actor.SetCostume(app.loadActorCostume(actor, cmd.Costume))
 	}
+	actor.Footprint = cmd.Footprint
 	actor.Size = cmd.Size
+	if cmd.Speed != (Positionf{}) {
+		actor.SetSpeed(cmd.Speed)
+	}
 	actor.TalkColor = cmd.TalkColor
 	actor.UsePos = cmd.UsePos
 	actor.UseDir = cmd.UseDir
 	actor.scriptLoc = cmd.ScriptLoc
+	actor.SetRoutine(app, cmd.Routine)
 	done.CompleteWithValue(cmd)
 }
 
+// ActorSetCostume is a command that will change the costume of an actor. A null reference takes
+// the costume off, so the actor is not drawn anymore.
+type ActorSetCostume struct {
+	Actor   *Actor
+	Costume ResourceRef
+}
+
+func (cmd ActorSetCostume) Execute(app *App, done *Promise) {
+	var costume *Costume
+	if cmd.Costume != ResourceRefNull {
+		costume = app.loadActorCostume(cmd.Actor, cmd.Costume)
+	}
+	cmd.Actor.SetCostume(costume)
+	done.CompleteWithValue(cmd.Actor)
+}
+
+// loadActorCostume returns the costume with the given reference for the actor. Costumes are
+// loaded once per actor, so actors can change their costumes back and forth cheaply. They are
+// not shared among actors, since each actor plays their animations at its own pace.
+func (a *App) loadActorCostume(actor *Actor, ref ResourceRef) *Costume {
+	if costume, ok := actor.wardrobe[ref]; ok {
+		return costume
+	}
+	costume := a.res.LoadCostume(ref)
+	actor.wardrobe[ref] = costume
+	return costume
+}
+
 // ActorShow is a command that will show an actor in the room at the given position.
 type ActorShow struct {
 	Actor    *Actor
@@ -44,12 +81,122 @@ func (cmd ActorShow) Execute(app *App, done *Promise) {
 		done.CompleteWithErrorf("no active room to show actor %s", cmd.Actor.Name())
 		return
 	}
+	placeActor(app.room, cmd.Actor, cmd.Position, cmd.LookAt)
+	done.Complete()
+}
+
+// ActorHide is a command that will take an actor out of the room it is in.
+type ActorHide struct {
+	Actor *Actor
+}
 
-	app.room.PutActor(cmd.Actor)
-	cmd.Actor.Locate(app.room, cmd.Position, cmd.LookAt)
+func (cmd ActorHide) Execute(app *App, done *Promise) {
+	if room := cmd.Actor.Room(); room != nil {
+		cmd.Actor.CancelAction()
+		room.RemoveActor(cmd.Actor)
+	}
+	done.Complete()
+}
+
+// ActorMoveTo is a command that will move an actor to a position of a room, which is not
+// necessarily the current one. If no room is given, the actor stays in the room it is in.
+type ActorMoveTo struct {
+	Actor    *Actor
+	Room     *Room
+	Position Position
+	LookAt   Direction
+}
+
+func (cmd ActorMoveTo) Execute(app *App, done *Promise) {
+	room := cmd.Room
+	if room == nil {
+		room = cmd.Actor.Room()
+	}
+	if room == nil {
+		done.CompleteWithErrorf("no room to move actor %s to", cmd.Actor.Name())
+		return
+	}
+	placeActor(room, cmd.Actor, cmd.Position, cmd.LookAt)
 	done.Complete()
 }
 
+// placeActor puts an actor in a room at the given position and direction. The actors following it
+// across rooms are put in the new room as well, right where it is.
+func placeActor(room *Room, actor *Actor, pos Position, dir Direction) {
+	if !room.IsWalkable(pos) {
+		log.Printf(
+			"Warning: room %s: actor %s shown at %v, out of the walkable area",
+			room.id, actor.id, pos,
+		)
+	}
+	prev := actor.Room()
+	room.PutActor(actor)
+	actor.Locate(room, pos, dir)
+
+	if prev == nil || prev == room {
+		return
+	}
+	for _, follower := range slices.Clone(prev.actors) {
+		if follower.isFollowing(actor, true) {
+			// Do not locate the follower, since that would cancel the following action.
+			room.PutActor(follower)
+			follower.pos = actor.pos
+			follower.walkPath = nil
+		}
+	}
+}
+
+// ActorFollow is a command that will make an actor follow another one until it is told to do
+// something else. The command completes when the actor stops following.
+type ActorFollow struct {
+	Actor       *Actor
+	Target      *Actor
+	Distance    int  // The distance to keep from the followed actor
+	AcrossRooms bool // Follow the actor when it is moved to another room
+	Queue       bool // Follow after the current and queued actions instead of replacing them
+}
+
+func (cmd ActorFollow) Execute(app *App, done *Promise) {
+	if cmd.Actor == cmd.Target {
+		done.CompleteWithErrorf("actor %s cannot follow itself", cmd.Actor.Name())
+		return
+	}
+	if cmd.Distance <= 0 {
+		cmd.Distance = DefaultActorFollowDistance
+	}
+	action := Following(cmd.Target, cmd.Distance, cmd.AcrossRooms)
+	done.Bind(cmd.Actor.perform(action, cmd.Queue))
+}
+
+// ActorState is a snapshot of the state of an actor.
+type ActorState struct {
+	Position  Position
+	Direction Direction
+	Room      *Room // The room where the actor is, or nil if it is not in any room
+	Costume   ResourceRef
+	Speaking  bool
+	Walking   bool
+	Inventory []*Object
+}
+
+// ActorInspect is a command that will read the state of an actor. The command completes with an
+// ActorState value.
+type ActorInspect struct {
+	Actor *Actor
+}
+
+func (cmd ActorInspect) Execute(app *App, done *Promise) {
+	done.CompleteWithValue(ActorState{
+		Position:  cmd.Actor.Position(),
+		Direction: cmd.Actor.Direction(),
+		Room:      cmd.Actor.Room(),
+		Costume:   cmd.Actor.CostumeRef(),
+		Speaking:  cmd.Actor.IsSpeaking(),
+		Walking:   cmd.Actor.IsWalking(),
+		Inventory: slices.Clone(cmd.Actor.Inventory()),
+	})
+}
+
 // ActorLookAtPos is a command that will make an actor look at a given position.
 type ActorLookAtPos struct {
 	Actor    *Actor
@@ -64,17 +211,96 @@ func (cmd ActorLookAtPos) Execute(app *App, done *Promise) {
 type ActorStand struct {
 	Actor     *Actor
 	Direction Direction
+	Queue     bool // Stand after the current and queued actions instead of replacing them
 }
 
 func (cmd ActorStand) Execute(app *App, done *Promise) {
+	if cmd.Queue {
+		// Standing never completes. Turn to complete once the actor faces the direction.
+		done.Bind(cmd.Actor.Enqueue(Turning(cmd.Direction)))
+		return
+	}
 	cmd.Actor.Do(Standing(cmd.Direction))
 	done.Complete()
 }
 
-// ActorWalkToPosition is a command that will make an actor walk to a given position.
+// ActorAnimate is a command that will make an actor play a custom animation of its costume. The
+// action is given either by code or, if Name is not empty, by name. One-shot animations complete
+// the command when they end, while looping animations complete it as soon as they start.
+type ActorAnimate struct {
+	Actor  *Actor
+	Action CostumeAction
+	Name   string
+	Loop   bool
+	Queue  bool // Animate after the current and queued actions instead of replacing them
+}
+
+func (cmd ActorAnimate) Execute(app *App, done *Promise) {
+	cos := cmd.Actor.costume
+	if cos == nil {
+		done.CompleteWithErrorf("actor %s has no costume", cmd.Actor.Name())
+		return
+	}
+	act := cmd.Action
+	if cmd.Name != "" {
+		var ok bool
+		if act, ok = cos.CustomAction(cmd.Name); !ok {
+			done.CompleteWithErrorf("actor %s has no action %s", cmd.Actor.Name(), cmd.Name)
+			return
+		}
+	}
+	if !cos.HasAnimation(act) {
+		done.CompleteWithErrorf("actor %s has no animation for action %d", cmd.Actor.Name(), act)
+		return
+	}
+
+	action := cmd.Actor.perform(Animating(act, cmd.Loop), cmd.Queue)
+	if cmd.Loop {
+		done.Complete()
+		return
+	}
+	done.Bind(action)
+}
+
+// ActorFace is a command that will make an actor turn to face a room item, or a position if no
+// item is given.
+type ActorFace struct {
+	Actor    *Actor
+	Item     RoomItem
+	Position Position
+	Queue    bool // Turn after the current and queued actions instead of replacing them
+}
+
+func (cmd ActorFace) Execute(app *App, done *Promise) {
+	target := cmd.Position
+	switch item := cmd.Item.(type) {
+	case *Actor:
+		target = item.Position()
+	case *Object:
+		target = item.Hotspot().Center()
+	}
+	done.Bind(cmd.Actor.perform(facing(target), cmd.Queue))
+}
+
+// ActorStop is a command that will make an actor stop doing its current action and the queued
+// ones. Their futures are broken.
+type ActorStop struct {
+	Actor *Actor
+}
+
+func (cmd ActorStop) Execute(app *App, done *Promise) {
+	cmd.Actor.CancelAction()
+	done.Complete()
+}
+
+// ActorWalkToPosition is a command that will make an actor walk to a given position. The actor
+// will follow the walk boxes of the room to reach the closest walkable position to the
+// destination.
 type ActorWalkToPosition struct {
 	Actor    *Actor
 	Position Position
+	Run      bool // Run instead of walking, according to the run mode of the application
+	Queue    bool // Walk after the current and queued actions instead of replacing them
 }
 
 func (cmd ActorWalkToPosition) Execute(app *App, done *Promise) {
@@ -82,16 +308,62 @@ func (cmd ActorWalkToPosition) Execute(app *App, done *Promise) {
 		done.CompleteWithErrorf("actor %s is not in the room", cmd.Actor.Name())
 		return
 	}
-	done.Bind(cmd.Actor.Do(WalkingTo(cmd.Position)))
+	walk := WalkingPath
+	if cmd.Run {
+		switch app.runMode {
+		case RunModeJump:
+			walk = func(path []Position) *Action {
+				if len(path) == 0 {
+					return JumpingTo(cmd.Position)
+				}
+				return JumpingTo(path[len(path)-1])
+			}
+		default:
+			walk = RunningPath
+		}
+	}
+	done.Bind(cmd.Actor.perform(pathTo(cmd.Position, walk), cmd.Queue))
+}
+
+// ActorSetRoutine is a command that will set the routine of an actor, replacing the previous one.
+// An empty routine removes it.
+type ActorSetRoutine struct {
+	Actor   *Actor
+	Routine []RoutineStep
+}
+
+func (cmd ActorSetRoutine) Execute(app *App, done *Promise) {
+	cmd.Actor.SetRoutine(app, cmd.Routine)
+	done.CompleteWithValue(cmd.Actor)
+}
+
+// ActorSetSpeed is a command that will change the walking speed of an actor.
+type ActorSetSpeed struct {
+	Actor *Actor
+	Speed Positionf
+}
+
+func (cmd ActorSetSpeed) Execute(app *App, done *Promise) {
+	if cmd.Speed.X <= 0 || cmd.Speed.Y <= 0 {
+		done.CompleteWithErrorf("invalid speed %v for actor %s", cmd.Speed, cmd.Actor.Name())
+		return
+	}
+	cmd.Actor.SetSpeed(cmd.Speed)
+	done.CompleteWithValue(cmd.Actor)
 }
 
 // ActorWalkToItem is a command that will make an actor walk to a room item.
 type ActorWalkToItem struct {
 	Actor *Actor
 	Item  RoomItem
+	Queue bool // Walk after the current and queued actions instead of replacing them
 }
 
 func (cmd ActorWalkToItem) Execute(app *App, done *Promise) {
+	if cmd.Actor.Room() != app.room {
+		done.CompleteWithErrorf("actor %s is not in the room", cmd.Actor.Name())
+		return
+	}
 	switch item := cmd.Item.(type) {
 	case *Actor:
 		if item.Room() != app.room {
@@ -101,21 +373,55 @@ func (cmd ActorWalkToItem) Execute(app *App, done *Promise) {
 	case *Object:
 		if item.Owner() != nil {
 			done.CompleteWithErrorf("object %s is in the inventory", item.Name())
+			return
 		}
 	}
 	pos, dir := cmd.Item.UsePosition()
 
-	done.Bind(app.RunCommandSequence(
-		ActorWalkToPosition{
-			Actor:    cmd.Actor,
-			Position: pos,
-		},
-		ActorStand{
-			Actor:     cmd.Actor,
-			Direction: dir,
-		},
-	))
+	// Turning is queued right after walking, so nothing queued later gets in between.
+	cmd.Actor.perform(pathTo(pos, WalkingPath), cmd.Queue)
+	done.Bind(cmd.Actor.Enqueue(Turning(dir)))
+}
+
+// ActorExitThrough is a command that will make an actor go through an exit object to the room it
+// leads to. The ego makes that room be shown, while other actors are just moved to that room. The
+// actor is put at the entry point of the exit, if any. Otherwise, the enter function of the room
+// is expected to place the ego. Nothing happens if the exit is closed. The command does not wait
+// for the room to be shown, which lasts until its enter function is finished.
+type ActorExitThrough struct {
+	Actor  *Actor
+	Object *Object
+}
 
+func (cmd ActorExitThrough) Execute(app *App, done *Promise) {
+	exit := cmd.Object.Exit()
+	if exit == nil {
+		done.CompleteWithErrorf("object %s is not an exit", cmd.Object.id)
+		return
+	}
+	if !cmd.Object.IsExitOpen() {
+		done.Complete()
+		return
+	}
+	room := app.FindRoom(exit.Room)
+	if room == nil {
+		done.CompleteWithErrorf("room %s of exit %s not found", exit.Room, cmd.Object.id)
+		return
+	}
+	entry := RoomEntry{Pos: cmd.Actor.Position(), Dir: cmd.Actor.Direction()}
+	if exit.Entry != "" {
+		var ok bool
+		if entry, ok = room.Entry(exit.Entry); !ok {
+			done.CompleteWithErrorf("entry %s not found in room %s", exit.Entry, room.id)
+			return
+		}
+	}
+	if cmd.Actor == app.ego {
+		app.RunCommand(RoomShow{Room: room, Entry: exit.Entry})
+	} else {
+		placeActor(room, cmd.Actor, entry.Pos, entry.Dir)
+	}
+	done.Complete()
 }
 
 // ActorInteractWith is a command that will make an actor interact with an object.
@@ -230,6 +536,19 @@ func (cmd ActorInteractWith) Execute(app *App, done *Promise) {
 		}
 		return nil
 	})
+
+	// The actors the ego interacts with stop their routines until the interaction is over.
+	for _, target := range cmd.Targets {
+		if actor, ok := target.(*Actor); ok && actor != cmd.Actor {
+			actor.pauseRoutine()
+			completed = Continue(completed, func(v any) Future {
+				return app.RunCommand(CommandFunc(func(*App) (any, error) {
+					actor.resumeRoutine()
+					return v, nil
+				}))
+			})
+		}
+	}
 	done.Bind(completed)
 }
 
@@ -239,6 +558,7 @@ type ActorSpeak struct {
 	Text  string
 	Delay time.Duration
 	Color Color
+	Queue bool // Speak after the current and queued actions instead of replacing them
 }
 
 func (cmd ActorSpeak) Execute(app *App, done *Promise) {
@@ -250,14 +570,7 @@ func (cmd ActorSpeak) Execute(app *App, done *Promise) {
 		cmd.Color = cmd.Actor.TalkColor
 	}
 
-	dialogDone := app.RunCommand(ShowDialog{
-		Actor:    cmd.Actor,
-		Text:     cmd.Text,
-		Position: cmd.Actor.dialogPos(),
-		Color:    cmd.Color,
-		Speed:    1.0,
-	})
-	done.Bind(cmd.Actor.Do(SpeakingTo(dialogDone)))
+	done.Bind(cmd.Actor.perform(Speaking(app, cmd.Text, cmd.Color), cmd.Queue))
 }
 
 // ActorSelectEgo is a command that will make an actor be the actor under player's control.
@@ -0,0 +1,33 @@
+package pctk
+
+// CameraSetPosition is a command that will move the camera immediately to the given position of
+// the room. The position is the top-left corner of the viewport.
+type CameraSetPosition struct {
+	Position Position
+}
+
+func (cmd CameraSetPosition) Execute(app *App, done *Promise) {
+	app.camera.SetPosition(cmd.Position)
+	done.Complete()
+}
+
+// CameraPanTo is a command that will move the camera smoothly to the given position of the room.
+// The position is the top-left corner of the viewport. The command is completed when the camera
+// arrives.
+type CameraPanTo struct {
+	Position Position
+}
+
+func (cmd CameraPanTo) Execute(app *App, done *Promise) {
+	done.Bind(app.camera.PanTo(cmd.Position))
+}
+
+// CameraFollow is a command that will make the camera follow an actor, or stop following if the
+// actor is nil. The command is completed when the actor is in the dead zone of the camera.
+type CameraFollow struct {
+	Actor *Actor
+}
+
+func (cmd CameraFollow) Execute(app *App, done *Promise) {
+	done.Bind(app.camera.Follow(cmd.Actor))
+}
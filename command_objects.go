@@ -1,8 +1,17 @@
 package pctk
 
+import (
+	"log"
+	"strconv"
+)
+
 // ObjectDeclare is a command that will declare a new object with the given properties.
+//
+// If the hotspot or the use position are zero values, they are taken from the geometry of the room
+// matching the object ID, if any.
 type ObjectDeclare struct {
 	Class     ObjectClass
+	Exit      *ObjectExit // The exit the object leads to, or nil if it is not an exit
 	Hotspot   Rectangle
 	Name      string
 	ObjectID  string
@@ -10,6 +19,8 @@ type ObjectDeclare struct {
 	RoomID    string
 	ScriptLoc FieldAccessor // The location of the object in the script
 	Sprites   ResourceRef
+	State     int    // The index of the initial state
+	StateName string // The name of the initial state, which takes precedence over the index if given
 	States    []*ObjectState
 	UseDir    Direction
 	UsePos    Position
@@ -22,8 +33,25 @@ func (cmd ObjectDeclare) Execute(app *App, done *Promise) {
 		sprites = app.res.LoadSpriteSheet(cmd.Sprites)
 	}
 
+	if geom := room.geometry; geom != nil {
+		if hotspot, ok := geom.Hotspots[cmd.ObjectID]; ok && cmd.Hotspot == (Rectangle{}) {
+			cmd.Hotspot = hotspot
+		}
+		if usePos, ok := geom.UsePositions[cmd.ObjectID]; ok && cmd.UsePos == (Position{}) {
+			cmd.UsePos = usePos
+		}
+	}
+
+	if cmd.UsePos != (Position{}) && !room.IsWalkable(cmd.UsePos) {
+		log.Printf(
+			"Warning: room %s: use position %v of object %s is out of the walkable area",
+			cmd.RoomID, cmd.UsePos, cmd.ObjectID,
+		)
+	}
+
 	obj := &Object{
 		classes:   cmd.Class,
+		exit:      cmd.Exit,
 		hotspot:   cmd.Hotspot,
 		id:        cmd.ObjectID,
 		name:      cmd.Name,
@@ -36,9 +64,41 @@ func (cmd ObjectDeclare) Execute(app *App, done *Promise) {
 		usePos:    cmd.UsePos,
 	}
 	room.DeclareObject(obj)
+	if cmd.State != 0 || cmd.StateName != "" {
+		ObjectSetState{Object: obj, State: cmd.State, Name: cmd.StateName}.Execute(app, done)
+		return
+	}
 	done.Complete()
 }
 
+// ObjectSetState is a command that will change the state of an object. The state is given by its
+// name, or by its index if the name is empty.
+type ObjectSetState struct {
+	Object *Object
+	State  int
+	Name   string
+}
+
+func (cmd ObjectSetState) Execute(app *App, done *Promise) {
+	state := cmd.State
+	if cmd.Name != "" {
+		state = cmd.Object.StateByName(cmd.Name)
+	}
+	if state < 0 || state >= len(cmd.Object.states) {
+		done.CompleteWithErrorf("object %s has no state %s", cmd.Object.id, cmd.stateRef())
+		return
+	}
+	cmd.Object.SetState(state)
+	done.Complete()
+}
+
+func (cmd ObjectSetState) stateRef() string {
+	if cmd.Name != "" {
+		return cmd.Name
+	}
+	return strconv.Itoa(cmd.State)
+}
+
 // ObjectCall is a command that will execute a script function of an object.
 type ObjectCall struct {
 	Object   *Object
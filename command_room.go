@@ -1,12 +1,27 @@
 package pctk
 
-import "log"
+import (
+	"log"
+	"slices"
+)
 
 // RoomDeclare is a command that will declare a new room with the given properties.
 type RoomDeclare struct {
 	BackgroundRef ResourceRef
+	Entries       map[string]RoomEntry // The entry points of the room, indexed by name
+	GeometryRef   ResourceRef          // The geometry of the room, or ResourceRefNull if not imported
 	RoomID        string
 	Script        *Script
+	Transition    Transition // The transition shown by default when the room is shown
+	WalkBoxes     []*WalkBox
+	ZPlanes       []RoomZPlane
+}
+
+// RoomZPlane is the declaration of a z-plane of a room.
+type RoomZPlane struct {
+	ID       string
+	ImageRef ResourceRef
+	Baseline int
 }
 
 func (cmd RoomDeclare) Execute(app *App, done *Promise) {
@@ -16,29 +31,132 @@ func (cmd RoomDeclare) Execute(app *App, done *Promise) {
 	room := Room{
 		id:         cmd.RoomID,
 		background: app.res.LoadImage(cmd.BackgroundRef),
+		entries:    cmd.Entries,
 		script:     cmd.Script,
+		transition: cmd.Transition,
+	}
+	walkboxes := cmd.WalkBoxes
+	if cmd.GeometryRef != ResourceRefNull {
+		room.geometry = app.res.LoadRoomGeometry(cmd.GeometryRef)
+		if room.geometry == nil {
+			done.CompleteWithErrorf("geometry %s of room %s not found", cmd.GeometryRef, cmd.RoomID)
+			return
+		}
+		// The walk boxes declared explicitly take precedence over the imported ones.
+		for _, walkbox := range room.geometry.WalkBoxes {
+			if !slices.ContainsFunc(walkboxes, func(w *WalkBox) bool { return w.ID() == walkbox.ID() }) {
+				walkboxes = append(walkboxes, walkbox)
+			}
+		}
+	}
+	if len(walkboxes) > 0 {
+		room.walkboxes = NewWalkBoxMatrix(walkboxes)
+		for _, err := range room.walkboxes.Validate() {
+			log.Printf("Warning: room %s: %v", cmd.RoomID, err)
+		}
+	}
+	for _, name := range sortedKeys(cmd.Entries) {
+		if pos := cmd.Entries[name].Pos; !room.IsWalkable(pos) {
+			log.Printf("Warning: room %s: entry %s at %v is out of the walkable area", cmd.RoomID, name, pos)
+		}
+	}
+	for _, zplane := range cmd.ZPlanes {
+		room.zplanes = append(room.zplanes, NewZPlane(zplane.ID, app.res.LoadImage(zplane.ImageRef), zplane.Baseline))
+	}
+	for _, walkbox := range walkboxes {
+		for _, id := range walkbox.ZPlanes() {
+			if !slices.ContainsFunc(room.zplanes, func(z *ZPlane) bool { return z.ID() == id }) {
+				log.Printf("Warning: room %s: walk box %s declares unknown z-plane %s", cmd.RoomID, walkbox.ID(), id)
+			}
+		}
 	}
 	app.rooms[cmd.RoomID] = &room
 	done.CompleteWithValue(room)
 }
 
-// RoomShow is a command that will show the room with the given resource.
+// RoomShow is a command that will show the room with the given resource. The transition is the
+// default one of the room unless given. If an entry is given, the ego is put in the entry point of
+// the room with that name when the room is shown. The enter function of the room receives the ID
+// of the previous room and the entry as arguments, or nil if none. The ID is given instead of the
+// room, since the previous room might be declared in a script unknown to the one of this room.
+// The command is completed when the enter function of the room and the transition are finished.
 type RoomShow struct {
-	Room *Room
+	Room       *Room
+	Entry      string
+	Transition Transition
 }
 
 func (cmd RoomShow) Execute(app *App, done *Promise) {
 	var job Future
+	prev := app.room
+	entry, ok := cmd.Room.Entry(cmd.Entry)
+	if cmd.Entry != "" && !ok {
+		done.CompleteWithErrorf("entry %s not found in room %s", cmd.Entry, cmd.Room.id)
+		return
+	}
+	transition := cmd.Transition.
+		Or(cmd.Room.transition).
+		Or(Transition{Kind: TransitionCut, Duration: DefaultTransitionDuration})
 
-	if app.room != nil {
-		job = IgnoreError(app.room.script.Call(WithField(app.room.id, "exit"), nil, true), nil)
+	if prev != nil {
+		job = IgnoreError(prev.script.Call(WithField(prev.id, "exit"), nil, true), nil)
+		job = Continue(job, func(any) Future {
+			return app.RunCommand(CommandFunc(func(app *App) (any, error) {
+				return app.hideRoom(transition), nil
+			}))
+		})
+		job = Continue(job, func(hidden any) Future {
+			return hidden.(Future)
+		})
 	}
 
-	// Call the enter function of the room script.
-	app.room = cmd.Room
-	job = Continue(job, func(a any) Future {
-		return IgnoreError(cmd.Room.script.Call(WithField(cmd.Room.id, "enter"), nil, true), nil)
+	// Replace the room and call the enter function of the room script while the transition shows
+	// the room.
+	job = Continue(job, func(any) Future {
+		return app.RunCommand(CommandFunc(func(app *App) (any, error) {
+			app.room = cmd.Room
+			if cmd.Entry != "" && app.ego != nil {
+				placeActor(cmd.Room, app.ego, entry.Pos, entry.Dir)
+			}
+			app.camera.Reset(cmd.Room)
+			return app.showRoom(transition), nil
+		}))
+	})
+	job = Continue(job, func(shown any) Future {
+		args := []any{nil, nil}
+		if prev != nil {
+			args[0] = prev.id
+		}
+		if cmd.Entry != "" {
+			args[1] = cmd.Entry
+		}
+		entered := IgnoreError(cmd.Room.script.Call(WithField(cmd.Room.id, "enter"), args, true), nil)
+		return Continue(entered, func(any) Future {
+			return shown.(Future)
+		})
 	})
 
 	done.Bind(job)
 }
+
+// RoomEnableWalkBox is a command that will enable or disable a walk box of the room. This is
+// typically used to open or close walkable areas, such as doors or bridges, during the game.
+type RoomEnableWalkBox struct {
+	Room      *Room
+	WalkBoxID string
+	Enabled   bool
+}
+
+func (cmd RoomEnableWalkBox) Execute(app *App, done *Promise) {
+	if cmd.Room.walkboxes == nil {
+		done.CompleteWithErrorf("room %s has no walk boxes", cmd.Room.id)
+		return
+	}
+	id := cmd.Room.walkboxes.WalkBoxIndex(cmd.WalkBoxID)
+	if id == InvalidWalkBox {
+		done.CompleteWithErrorf("walk box %s not found in room %s", cmd.WalkBoxID, cmd.Room.id)
+		return
+	}
+	cmd.Room.walkboxes.EnableWalkBox(id, cmd.Enabled)
+	done.Complete()
+}
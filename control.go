@@ -2,6 +2,7 @@ package pctk
 
 import (
 	"strings"
+	"time"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -15,6 +16,13 @@ var (
 	ControlVerbHoverColor      = Yellow
 )
 
+const (
+	// ControlDoubleClickDelay is the maximum time between two clicks to be a double click.
+	ControlDoubleClickDelay = 400 * time.Millisecond
+	// ControlDoubleClickDistance is the maximum distance between two clicks to be a double click.
+	ControlDoubleClickDistance = 4
+)
+
 // Verb is a type that represents the action verb.
 type Verb string
 
@@ -55,7 +63,7 @@ func (s VerbSlot) Draw(app *App, m *MouseCursor) {
 		color = ControlVerbHoverColor
 	}
 	if room := app.room; room != nil {
-		if item := room.ItemAt(m.Position()); item != nil {
+		if item := room.ItemAt(app.camera.ToRoom(m.Position())); item != nil {
 			switch s.Verb {
 			case VerbOpen:
 				if item.Class().IsOneOf(ObjectClassOpenable) {
@@ -94,6 +102,9 @@ type ActionSentence struct {
 	verb Verb
 	args [2]RoomItem
 	fut  Future
+
+	lastWalkClick     Position
+	lastWalkClickTime time.Time
 }
 
 // Draw renders the action sentence in the control pane.
@@ -231,36 +242,61 @@ func (s *ActionSentence) interactWith(app *App, verb Verb, item, other RoomItem)
 	s.args[0] = item
 	s.args[1] = other
 
-	var cmd Command
+	var cmds []Command
 	if verb == VerbWalkTo {
-		cmd = ActorWalkToItem{
+		cmds = append(cmds, ActorWalkToItem{
 			Actor: app.ego,
 			Item:  item,
+			Queue: shiftDown(),
+		})
+		// Walking onto an exit goes through it.
+		if obj, ok := item.(*Object); ok && obj.Exit() != nil {
+			cmds = append(cmds, ActorExitThrough{
+				Actor:  app.ego,
+				Object: obj,
+			})
 		}
 	} else {
-		cmd = ActorInteractWith{
+		cmds = append(cmds, ActorInteractWith{
 			Actor:   app.ego,
 			Targets: [2]RoomItem{item, other},
 			Verb:    verb,
-		}
+		})
 	}
-	s.fut = app.RunCommandSequence(
-		cmd,
-		CommandFunc(func(app *App) (any, error) {
-			s.Reset(VerbWalkTo)
-			return nil, nil
-		}),
-	)
+	cmds = append(cmds, CommandFunc(func(app *App) (any, error) {
+		s.Reset(VerbWalkTo)
+		return nil, nil
+	}))
+	s.fut = app.RunCommandSequence(cmds[0], cmds[1:]...)
 }
 
 func (s *ActionSentence) walkToPos(app *App, click Position) {
+	// Double clicking on the same spot makes the ego run.
+	now := time.Now()
+	dist := s.lastWalkClick.Distance(click)
+	run := now.Sub(s.lastWalkClickTime) <= ControlDoubleClickDelay &&
+		dist.W <= ControlDoubleClickDistance && dist.H <= ControlDoubleClickDistance
+	s.lastWalkClick, s.lastWalkClickTime = click, now
+	if run {
+		// Do not take a triple click as another double click.
+		s.lastWalkClickTime = time.Time{}
+	}
+
 	app.RunCommand(ActorWalkToPosition{
 		Actor:    app.ego,
 		Position: click,
+		Run:      run,
+		Queue:    shiftDown(),
 	})
 	s.Reset(VerbWalkTo)
 }
 
+// shiftDown returns true if any shift key is down. Walking while holding shift queues the walk
+// after the ongoing ones.
+func shiftDown() bool {
+	return rl.IsKeyDown(rl.KeyLeftShift) || rl.IsKeyDown(rl.KeyRightShift)
+}
+
 // Reset resets the action sentence to the given verb.
 func (s *ActionSentence) Reset(verb Verb) {
 	s.verb = verb
@@ -369,7 +405,7 @@ func (p *ControlPane) Draw(app *App) {
 func (p *ControlPane) hover(app *App, pos Position) RoomItem {
 	var item RoomItem
 	if ViewportRect.Contains(pos) && app.room != nil {
-		item = app.room.ItemAt(pos)
+		item = app.room.ItemAt(app.camera.ToRoom(pos))
 	} else if ControlPaneRect.Contains(pos) {
 		if obj := p.inv.ObjectAt(app, pos); obj != nil {
 			item = obj
@@ -386,14 +422,14 @@ func (p *ControlPane) processControlInputs(app *App) {
 	hover := p.hover(app, pos)
 	if rl.IsMouseButtonPressed(rl.MouseButtonLeft) {
 		if ViewportRect.Contains(pos) {
-			p.action.ProcessLeftClick(app, pos, hover)
+			p.action.ProcessLeftClick(app, app.camera.ToRoom(pos), hover)
 		}
 		if ControlPaneRect.Contains(pos) {
 			p.processLeftClick(app, pos)
 		}
 	} else if rl.IsMouseButtonPressed(rl.MouseButtonRight) {
 		if ViewportRect.Contains(pos) {
-			p.action.ProcessRightClick(app, pos, hover)
+			p.action.ProcessRightClick(app, app.camera.ToRoom(pos), hover)
 		}
 	}
 }
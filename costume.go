@@ -2,26 +2,76 @@ package pctk
 
 import (
 	"io"
+	"math/rand/v2"
+	"slices"
+	"time"
 )
 
 // CostumeAction is a value that represents an action for a costume. For predefined actions idle,
-// speak, and walk, use the CustomIdle, CustomSpeak, and CustomWalk functions respectively to refer
-// to them. For custom actions, use any custom byte value above 0x80.
+// speak, walk and turn, use the CustomIdle, CustomSpeak, CustomWalk and CostumeTurn functions
+// respectively to refer to them. For custom actions, use any custom byte value above 0x80. Custom
+// actions can also be given a name in the costume (see Costume.WithCustomAction).
+//
+// Predefined actions encode the action kind in bits 2-5 and the direction in bits 0-1. Diagonal
+// directions are flagged with bit 6, so the cardinal ones keep the same values they always had.
 type CostumeAction byte
 
+const (
+	costumeActionKindIdle byte = iota
+	costumeActionKindSpeak
+	costumeActionKindWalk
+	costumeActionKindTurn
+
+	costumeActionDiagonal CostumeAction = 0x40
+	costumeActionCustom   CostumeAction = 0x80
+)
+
 // CostumeIdle returns a costume action for the idle action in the given direction.
 func CostumeIdle(dir Direction) CostumeAction {
-	return CostumeAction((0 << 2) | (dir & 0x03))
+	return costumeAction(costumeActionKindIdle, dir)
 }
 
 // CostumeSpeak returns a costume action for the speak action in the given direction.
 func CostumeSpeak(dir Direction) CostumeAction {
-	return CostumeAction((1 << 2) | (dir & 0x03))
+	return costumeAction(costumeActionKindSpeak, dir)
 }
 
 // CostumeWalk returns a costume action for the walk action in the given direction.
 func CostumeWalk(dir Direction) CostumeAction {
-	return CostumeAction((2 << 2) | (dir & 0x03))
+	return costumeAction(costumeActionKindWalk, dir)
+}
+
+// CostumeTurn returns a costume action for the turn action in the given direction. It is played
+// once when the actor faces that direction in the way to another one.
+func CostumeTurn(dir Direction) CostumeAction {
+	return costumeAction(costumeActionKindTurn, dir)
+}
+
+func costumeAction(kind byte, dir Direction) CostumeAction {
+	act := CostumeAction((kind << 2) | byte(dir&0x03))
+	if dir.IsDiagonal() {
+		act |= costumeActionDiagonal
+	}
+	return act
+}
+
+// Direction returns the direction of a predefined costume action, and whether the action is a
+// predefined one.
+func (act CostumeAction) Direction() (Direction, bool) {
+	if act >= costumeActionCustom {
+		return 0, false
+	}
+	dir := Direction(act & 0x03)
+	if act&costumeActionDiagonal != 0 {
+		dir += DirUpRight
+	}
+	return dir, true
+}
+
+// withDirection returns the same predefined costume action in another direction.
+func (act CostumeAction) withDirection(dir Direction) CostumeAction {
+	kind := byte(act&^costumeActionDiagonal) >> 2
+	return costumeAction(kind, dir)
 }
 
 // Costume is a struct that represents a costume for an actor or a room animation.
@@ -29,6 +79,11 @@ type Costume struct {
 	sprites *SpriteSheet
 
 	anims map[CostumeAction]*Animation
+	names map[string]CostumeAction
+
+	fidgets        []CostumeAction
+	fidgetDelay    time.Duration
+	fidgetInterval time.Duration
 }
 
 // NewCostume creates a new costume.
@@ -36,6 +91,7 @@ func NewCostume(sprites *SpriteSheet) *Costume {
 	return &Costume{
 		sprites: sprites,
 		anims:   make(map[CostumeAction]*Animation),
+		names:   make(map[string]CostumeAction),
 	}
 }
 
@@ -45,12 +101,83 @@ func (c *Costume) WithAnimation(act CostumeAction, anim *Animation) *Costume {
 	return c
 }
 
+// WithCustomAction gives a name to a custom action of the costume, so scripts can refer to it.
+func (c *Costume) WithCustomAction(name string, act CostumeAction) *Costume {
+	c.names[name] = act
+	return c
+}
+
+// CustomAction returns the custom action with the given name, and whether it exists.
+func (c *Costume) CustomAction(name string) (CostumeAction, bool) {
+	act, ok := c.names[name]
+	return act, ok
+}
+
+// NextCustomAction returns the lowest custom action that has no animation nor name yet, and
+// whether there is any left.
+func (c *Costume) NextCustomAction() (CostumeAction, bool) {
+	used := make(map[CostumeAction]bool, len(c.names))
+	for _, act := range c.names {
+		used[act] = true
+	}
+	for act := costumeActionCustom; act != 0; act++ {
+		if !used[act] && c.anims[act] == nil {
+			return act, true
+		}
+	}
+	return 0, false
+}
+
+// WithFidgets sets the actions played now and then by actors standing still. The first fidget is
+// played after the actor is idle for the given delay plus a random time up to the given interval,
+// and so on after each fidget.
+func (c *Costume) WithFidgets(delay, interval time.Duration, acts ...CostumeAction) *Costume {
+	c.fidgets = acts
+	c.fidgetDelay = delay
+	c.fidgetInterval = interval
+	return c
+}
+
+// nextFidget returns the time to wait before the next fidget. It returns false if the costume has
+// no fidgets.
+func (c *Costume) nextFidget() (time.Duration, bool) {
+	if c == nil || len(c.fidgets) == 0 {
+		return 0, false
+	}
+	wait := c.fidgetDelay
+	if c.fidgetInterval > 0 {
+		wait += rand.N(c.fidgetInterval)
+	}
+	return wait, true
+}
+
+// randomFidget returns the animation of one of the fidgets of the costume, chosen at random.
+func (c *Costume) randomFidget() *Animation {
+	return c.animation(c.fidgets[rand.IntN(len(c.fidgets))])
+}
+
+// HasAnimation returns true if the costume has an animation for the given action.
+func (c *Costume) HasAnimation(act CostumeAction) bool {
+	return c.animation(act) != nil
+}
+
 // BinaryEncode encodes the costume to a binary format. The format is as follows:
 // - sprite sheet.
 // - uint32: the number of animations.
 // - for each animation:
 //   - byte: the action.
 //   - the animation.
+//
+// - uint32: the number of custom action names.
+// - for each name:
+//   - string: the name.
+//   - byte: the action.
+//
+// - uint64: the minimum idle time before fidgeting.
+// - uint64: the maximum random time added to the minimum one.
+// - uint32: the number of fidgets.
+// - for each fidget:
+//   - byte: the action.
 func (c *Costume) BinaryEncode(w io.Writer) (n int, err error) {
 	n, err = BinaryEncode(w, c.sprites, uint32(len(c.anims)))
 	for act, anim := range c.anims {
@@ -60,6 +187,32 @@ func (c *Costume) BinaryEncode(w io.Writer) (n int, err error) {
 			return n, err
 		}
 	}
+
+	nn, err := BinaryEncode(w, uint32(len(c.names)))
+	n += nn
+	if err != nil {
+		return n, err
+	}
+	for _, name := range sortedKeys(c.names) {
+		nn, err := BinaryEncode(w, name, byte(c.names[name]))
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+
+	nn, err = BinaryEncode(w, uint64(c.fidgetDelay), uint64(c.fidgetInterval), uint32(len(c.fidgets)))
+	n += nn
+	if err != nil {
+		return n, err
+	}
+	for _, act := range c.fidgets {
+		nn, err := BinaryEncode(w, byte(act))
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
 	return n, nil
 }
 
@@ -67,6 +220,7 @@ func (c *Costume) BinaryEncode(w io.Writer) (n int, err error) {
 func (c *Costume) BinaryDecode(r io.Reader) error {
 	c.sprites = new(SpriteSheet)
 	c.anims = make(map[CostumeAction]*Animation)
+	c.names = make(map[string]CostumeAction)
 
 	var count uint32
 	if err := BinaryDecode(r, c.sprites, &count); err != nil {
@@ -80,11 +234,97 @@ func (c *Costume) BinaryDecode(r io.Reader) error {
 		}
 		c.anims[CostumeAction(act)] = anim
 	}
+
+	if err := BinaryDecode(r, &count); err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		var name string
+		var act byte
+		if err := BinaryDecode(r, &name, &act); err != nil {
+			return err
+		}
+		c.names[name] = CostumeAction(act)
+	}
+
+	var delay, interval uint64
+	if err := BinaryDecode(r, &delay, &interval, &count); err != nil {
+		return err
+	}
+	c.fidgetDelay = time.Duration(delay)
+	c.fidgetInterval = time.Duration(interval)
+	c.fidgets = make([]CostumeAction, count)
+	for i := range c.fidgets {
+		var act byte
+		if err := BinaryDecode(r, &act); err != nil {
+			return err
+		}
+		c.fidgets[i] = CostumeAction(act)
+	}
 	return nil
 }
 
-func (c *Costume) draw(act CostumeAction, pos Position) {
+func (c *Costume) draw(act CostumeAction, pos Position, scale float32) {
+	if anim := c.animation(act); anim != nil {
+		anim.DrawScaled(c.sprites, pos, scale)
+	}
+}
+
+// animation returns the animation for the given action. Costumes without animations for diagonal
+// directions fall back to the horizontal one, or the vertical one otherwise.
+func (c *Costume) animation(act CostumeAction) *Animation {
 	if anim := c.anims[act]; anim != nil {
-		anim.Draw(c.sprites, pos)
+		return anim
+	}
+	dir, ok := act.Direction()
+	if !ok || !dir.IsDiagonal() {
+		return nil
+	}
+	horizontal, vertical := dir.Cardinals()
+	if anim := c.anims[act.withDirection(horizontal)]; anim != nil {
+		return anim
+	}
+	return c.anims[act.withDirection(vertical)]
+}
+
+// compass is the sequence of directions an actor faces when turning clockwise.
+var compass = []Direction{
+	DirUp, DirUpRight, DirRight, DirDownRight, DirDown, DirDownLeft, DirLeft, DirUpLeft,
+}
+
+// TurnSteps returns the directions an actor wearing the costume faces when turning from one
+// direction to another, not including any of them. Only directions with a turn animation are
+// included, so actors with no turn animations just snap to the new direction. Actors turn the
+// shortest way, and when both ways are equally long they turn facing the camera.
+func (c *Costume) TurnSteps(from, to Direction) []Direction {
+	if c == nil {
+		return nil
+	}
+	ifrom, ito := slices.Index(compass, from), slices.Index(compass, to)
+	if ifrom < 0 || ito < 0 || ifrom == ito {
+		return nil
+	}
+	clockwise := (ito - ifrom + len(compass)) % len(compass)
+	step := 1
+	if clockwise > len(compass)/2 {
+		step = -1
+	}
+	if clockwise == len(compass)/2 {
+		// Opposite directions. Turn through the down direction, if it is in the way.
+		idown := slices.Index(compass, DirDown)
+		if (idown-ifrom+len(compass))%len(compass) > clockwise {
+			step = -1
+		}
+	}
+
+	var steps []Direction
+	for i := ifrom; ; {
+		i = (i + step + len(compass)) % len(compass)
+		if i == ito {
+			return steps
+		}
+		if c.anims[CostumeTurn(compass[i])] != nil {
+			steps = append(steps, compass[i])
+		}
 	}
 }
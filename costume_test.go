@@ -0,0 +1,80 @@
+package pctk_test
+
+import (
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCostumeActionDirection(t *testing.T) {
+	dirs := []pctk.Direction{
+		pctk.DirRight, pctk.DirLeft, pctk.DirUp, pctk.DirDown,
+		pctk.DirUpRight, pctk.DirDownRight, pctk.DirDownLeft, pctk.DirUpLeft,
+	}
+	seen := make(map[pctk.CostumeAction]bool)
+	for _, dir := range dirs {
+		for _, act := range []pctk.CostumeAction{
+			pctk.CostumeIdle(dir), pctk.CostumeSpeak(dir), pctk.CostumeWalk(dir),
+		} {
+			assert.False(t, seen[act], "action %d is duplicated", act)
+			seen[act] = true
+
+			actual, ok := act.Direction()
+			assert.True(t, ok)
+			assert.Equal(t, dir, actual)
+		}
+	}
+
+	// Cardinal directions keep their legacy encoding.
+	assert.Equal(t, pctk.CostumeAction(0x04|0x03), pctk.CostumeSpeak(pctk.DirDown))
+
+	_, ok := pctk.CostumeAction(0x81).Direction()
+	assert.False(t, ok)
+}
+
+func TestCostumeTurnSteps(t *testing.T) {
+	costume := pctk.NewCostume(nil)
+	assert.Empty(t, costume.TurnSteps(pctk.DirLeft, pctk.DirRight))
+
+	for _, dir := range []pctk.Direction{pctk.DirUp, pctk.DirDown, pctk.DirDownRight} {
+		costume.WithAnimation(pctk.CostumeTurn(dir), pctk.NewAnimation())
+	}
+	assert.Equal(t,
+		[]pctk.Direction{pctk.DirDown, pctk.DirDownRight},
+		costume.TurnSteps(pctk.DirLeft, pctk.DirRight),
+	)
+	assert.Equal(t,
+		[]pctk.Direction{pctk.DirDownRight, pctk.DirDown},
+		costume.TurnSteps(pctk.DirRight, pctk.DirLeft),
+	)
+	assert.Equal(t,
+		[]pctk.Direction{pctk.DirUp},
+		costume.TurnSteps(pctk.DirUpLeft, pctk.DirRight),
+	)
+	assert.Empty(t, costume.TurnSteps(pctk.DirRight, pctk.DirDownRight))
+	assert.Empty(t, costume.TurnSteps(pctk.DirDown, pctk.DirDown))
+}
+
+func TestCostumeCustomActions(t *testing.T) {
+	costume := pctk.NewCostume(nil)
+
+	act, ok := costume.NextCustomAction()
+	assert.True(t, ok)
+	assert.Equal(t, pctk.CostumeAction(0x80), act)
+
+	costume.WithAnimation(0x80, pctk.NewAnimation())
+	act, ok = costume.NextCustomAction()
+	assert.True(t, ok)
+	assert.Equal(t, pctk.CostumeAction(0x81), act)
+
+	costume.WithCustomAction("pickup", act).WithAnimation(act, pctk.NewAnimation())
+	act, ok = costume.CustomAction("pickup")
+	assert.True(t, ok)
+	assert.Equal(t, pctk.CostumeAction(0x81), act)
+	assert.True(t, costume.HasAnimation(act))
+
+	_, ok = costume.CustomAction("shrug")
+	assert.False(t, ok)
+	assert.False(t, costume.HasAnimation(0x82))
+}
@@ -0,0 +1,104 @@
+package pctk
+
+import (
+	"fmt"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// DefaultDebugOverlayKey is the key that toggles the debug overlay by default.
+const DefaultDebugOverlayKey = rl.KeyF12
+
+// Colors used to draw the shapes of the debug overlay.
+var (
+	debugWalkBoxColor         = BrigthGreen
+	debugDisabledWalkBoxColor = DarkGray
+	debugObjectColor          = Yellow
+	debugActorColor           = BrigthCyan
+	debugUsePosColor          = BrigthMagenta
+	debugWalkPathColor        = BrigthRed
+	debugMousePosColor        = White
+)
+
+// debugOverlay draws the geometry of the current room on top of the scene viewport. This is
+// useful to author rooms without guessing coordinates.
+type debugOverlay struct {
+	enabled bool
+	key     int32
+}
+
+// processInputs toggles the overlay when the debug key is pressed.
+func (d *debugOverlay) processInputs() {
+	if d.key != 0 && rl.IsKeyPressed(d.key) {
+		d.enabled = !d.enabled
+	}
+}
+
+// Draw renders the debug overlay for the current room of the application.
+func (d *debugOverlay) Draw(app *App) {
+	if !d.enabled || app.room == nil {
+		return
+	}
+	room := app.room
+
+	if room.walkboxes != nil {
+		for _, walkbox := range room.walkboxes.walkBoxes {
+			d.drawWalkBox(walkbox)
+		}
+	}
+	for _, obj := range room.objects {
+		if !obj.IsVisible() {
+			continue
+		}
+		d.drawHotspot(obj.hotspot, obj.id, debugObjectColor)
+		d.drawUsePosition(obj.usePos, obj.useDir)
+	}
+	for _, actor := range room.actors {
+		d.drawHotspot(actor.Hotspot(), actor.id, debugActorColor)
+		d.drawUsePosition(actor.UsePosition())
+		d.drawWalkPath(actor.Position(), actor.walkPath)
+	}
+
+	if mouse := app.control.cursor; mouse != nil && mouse.Enabled && mouse.OnScreen() {
+		pos := mouse.Position()
+		if ViewportRect.Contains(pos) {
+			// The overlay is drawn in room coordinates, so the text is moved along with the camera.
+			pos = app.camera.ToRoom(pos)
+			DrawDefaultText(fmt.Sprintf("%d,%d", pos.X, pos.Y), app.camera.ToRoom(NewPos(2, 2)), AlignLeft, debugMousePosColor)
+		}
+	}
+}
+
+func (d *debugOverlay) drawWalkBox(walkbox *WalkBox) {
+	color := debugWalkBoxColor
+	if !walkbox.enabled {
+		color = debugDisabledWalkBoxColor
+	}
+	numVertices := len(walkbox.vertices)
+	for i, vertex := range walkbox.vertices {
+		next := walkbox.vertices[(i+1)%numVertices]
+		rl.DrawLineV(rl.NewVector2(vertex.X, vertex.Y), rl.NewVector2(next.X, next.Y), color)
+	}
+	center := walkbox.center().ToPos()
+	DrawDefaultText(walkbox.walkBoxID, center.Above(FontDefaultSize/2), AlignCenter, color)
+}
+
+func (d *debugOverlay) drawHotspot(hotspot Rectangle, label string, color Color) {
+	rl.DrawRectangleLinesEx(hotspot.toRaylib(), 1, color)
+	DrawDefaultText(label, hotspot.Pos.Above(FontDefaultSize), AlignLeft, color)
+}
+
+func (d *debugOverlay) drawUsePosition(pos Position, dir Direction) {
+	rl.DrawCircleV(pos.toRaylib(), 2, debugUsePosColor)
+	offset := dir.Offset()
+	look := NewPos(offset.X*6, offset.Y*6)
+	rl.DrawLineV(pos.toRaylib(), pos.Add(look).toRaylib(), debugUsePosColor)
+}
+
+func (d *debugOverlay) drawWalkPath(from Position, path []Position) {
+	for _, waypoint := range path {
+		rl.DrawLineV(from.toRaylib(), waypoint.toRaylib(), debugWalkPathColor)
+		rl.DrawCircleV(waypoint.toRaylib(), 1, debugWalkPathColor)
+		from = waypoint
+	}
+}
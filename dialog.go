@@ -72,12 +72,17 @@ func (d *Dialog) Done() Future {
 	return d.done
 }
 
-// Draw will draw the dialog in the screen. It returns true if the dialog is completed.
-func (d *Dialog) Draw() {
+// Draw will draw the dialog in the screen. The dialogs spoken by actors are placed in the room, so
+// their position is translated to the viewport of the given camera.
+func (d *Dialog) Draw(cam *Camera) {
 	if d.done != nil && d.done.IsCompleted() {
 		return
 	}
-	DrawDialogText(d.text, d.pos, d.color)
+	pos := d.pos
+	if d.actor != nil {
+		pos = cam.ToViewport(pos)
+	}
+	DrawDialogText(d.text, pos, d.color)
 }
 
 // BeginDialog will prepare the dialog to be shown.
@@ -104,7 +109,7 @@ func (a *App) ClearDialogsFrom(actor *Actor) {
 func (a *App) drawDialogs() {
 	dialogs := make([]Dialog, 0, len(a.dialogs))
 	for _, d := range a.dialogs {
-		d.Draw()
+		d.Draw(&a.camera)
 		if !d.Done().IsCompleted() {
 			dialogs = append(dialogs, d)
 		}
@@ -174,6 +174,18 @@ func (e *ResourceEncoder) EncodeSpriteSheet(
 	})
 }
 
+// EncodeRoomGeometry encodes a room geometry using the resource encoder.
+func (e *ResourceEncoder) EncodeRoomGeometry(
+	id ResourceID,
+	g *RoomGeometry,
+	comp ResourceCompression,
+) error {
+	return e.encodeResource(id, g, resourceHeader{
+		Type:        resourceTypeRoomGeometry,
+		Compression: comp,
+	})
+}
+
 func (e *ResourceEncoder) encodeResource(id ResourceID, res BinaryEncoder, h resourceHeader) error {
 	var n int
 	var err error
@@ -277,6 +289,15 @@ func (l *ResourceFileLoader) LoadMusic(ref ResourceRef) *Music {
 	return m
 }
 
+func (l *ResourceFileLoader) LoadRoomGeometry(ref ResourceRef) *RoomGeometry {
+	if _, ok := l.findIndexEntry(ref); !ok {
+		return nil
+	}
+	g := NewRoomGeometry()
+	l.decodeResource(ref, resourceTypeRoomGeometry, g)
+	return g
+}
+
 func (l *ResourceFileLoader) LoadScript(ref ResourceRef) *Script {
 	script := new(Script)
 	l.decodeResource(ref, resourceTypeScript, script)
@@ -352,16 +373,21 @@ func (l *ResourceFileLoader) getResource(ref ResourceRef, t resourceType) []byte
 }
 
 func (l *ResourceFileLoader) getIndexEntry(ref ResourceRef) indexEntry {
+	entry, ok := l.findIndexEntry(ref)
+	if !ok {
+		log.Fatalf("resource not found: %s", ref)
+	}
+	return entry
+}
+
+func (l *ResourceFileLoader) findIndexEntry(ref ResourceRef) (indexEntry, bool) {
 	idx, ok := l.indexes[ref.Package()]
 	if !ok {
 		idx = l.loadIndex(ref)
 		l.indexes[ref.Package()] = idx
 	}
 	entry, ok := idx[ref.ID()]
-	if !ok {
-		log.Fatalf("resource not found: %s", ref)
-	}
-	return entry
+	return entry, ok
 }
 
 func (l *ResourceFileLoader) loadIndex(ref ResourceRef) index {
@@ -454,4 +480,5 @@ const (
 	resourceTypeScript
 	resourceTypeSound
 	resourceTypeSpriteSheet
+	resourceTypeRoomGeometry
 )
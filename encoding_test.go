@@ -3,6 +3,8 @@ package pctk_test
 import (
 	"bytes"
 	"encoding/binary"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/apoloval/pctk"
@@ -102,3 +104,27 @@ func TestResourceEncoder_EncodeResourceWithGZip(t *testing.T) {
 	assert.Equal(t, byte(0x01), dat[0x0B])            // dat hd compression
 	assert.Equal(t, make([]byte, 14), dat[0x0C:0x1A]) // dat hd reserved
 }
+
+func TestResourceFileLoader_LoadRoomGeometry(t *testing.T) {
+	dir := t.TempDir()
+	idx, err := os.Create(filepath.Join(dir, "resources.idx"))
+	require.NoError(t, err)
+	defer idx.Close()
+	dat, err := os.Create(filepath.Join(dir, "resources.dat"))
+	require.NoError(t, err)
+	defer dat.Close()
+
+	geom := pctk.NewRoomGeometry()
+	geom.Hotspots["door"] = pctk.NewRect(10, 20, 30, 60)
+	enc, err := pctk.NewResourceEncoder(idx, dat)
+	require.NoError(t, err)
+	require.NoError(t, enc.EncodeRoomGeometry("rooms/dock", geom, pctk.CompressionNone))
+
+	loader := pctk.NewResourceFileLoader(dir)
+	loaded := loader.LoadRoomGeometry(pctk.NewResourceRef("resources", "rooms/dock"))
+	require.NotNil(t, loaded)
+	assert.Equal(t, geom.Hotspots, loaded.Hotspots)
+
+	// A missing geometry is reported to the caller instead of aborting the program.
+	assert.Nil(t, loader.LoadRoomGeometry(pctk.NewResourceRef("resources", "rooms/town")))
+}
@@ -5,6 +5,7 @@ white = { r = 0xFF, g = 0xFF, b = 0xFF }
 guybrush = actor {
     name = "guybrush",
     costume = "resources:costumes/Guybrush",
+    footprint = {w=12, h=4},
     talkcolor = white
 }
 
@@ -3,6 +3,7 @@ include("resources:scripts/common")
 pirates = actor {
     name = "men of low moral fiber (pirates)",
     size = {w=60, h=64},
+    footprint = {w=60, h=10},
     talkcolor = magenta,
     usepos = {x=90, y=128},
     usedir = LEFT
@@ -10,6 +11,17 @@ pirates = actor {
 
 melee = room {
     background = "resources:backgrounds/Melee",
+    entries = {
+        dock = { pos = {x=340, y=140}, dir = LEFT },
+    },
+    walkboxes = {
+        street = { {x=0, y=112}, {x=479, y=112}, {x=479, y=143}, {x=0, y=143} },
+        alley = {
+            {x=140, y=92}, {x=200, y=92}, {x=230, y=112}, {x=110, y=112},
+            scale = {top=0.7, bottom=1},
+            speed = 0.8,
+        },
+    },
     objects = {
         bucket = object {
             class = APPLICABLE,
@@ -37,7 +49,7 @@ melee = room {
     }
 }
 
-function melee:enter()
+function melee:enter(prev, entry)
     local pirate1_dialog_props = { pos = {x=60, y=20}, color = magenta }
     local pirate2_dialog_props = { pos = {x=60, y=50}, color = yellow }
     local skipintro = true
@@ -47,10 +59,14 @@ function melee:enter()
         dir=RIGHT,
     }
 
-    guybrush:show{
-        pos={x=340, y=140}, 
-        dir=LEFT,
-    }
+    -- Coming through an exit, the ego is already at its entry point.
+    if not entry then
+        local dock = melee.entries.dock
+        guybrush:show{
+            pos=dock.pos,
+            dir=dock.dir,
+        }
+    end
     
     music1:play()
     cricket:play()
@@ -68,9 +84,11 @@ function melee:enter()
         sleep(2000)
         
         music2:play()
+        guybrush:setspeed(0.5)
         guybrush:walkto({x=120, y=120}).wait()
         guybrush:say("Where can I find the keys?", {delay=1000}).wait()
         guybrush:walkto({x=120, y=140}).wait()
+        guybrush:setspeed({x=80, y=20})
         guybrush:say("Ooooook...").wait()
         sleep(2000)
         guybrush:stand({dir = RIGHT}).wait()
@@ -79,7 +97,7 @@ function melee:enter()
         guybrush:stand({dir = LEFT}).wait()
         guybrush:say("Thank you guys!").wait()
         cricket:play()
-        guybrush:walkto({x=360, y=140}).wait()
+        guybrush:walkto({x=360, y=140}, {run=true}).wait()
         
         sayline("Oh, Jesus! I though he would\ntell again that stupid\ntale about LeChuck!", pirate1_dialog_props).wait()
         sleep(5000)
@@ -0,0 +1,65 @@
+package pctk
+
+import (
+	"testing"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// NewTestApp creates an application with no window, so commands and scripts can be run in tests.
+func NewTestApp(res ResourceLoader) *App {
+	return &App{
+		res:     res,
+		actors:  make(map[string]*Actor),
+		rooms:   make(map[string]*Room),
+		scripts: make(map[ResourceRef]*Script),
+	}
+}
+
+// Await executes the commands of the application until the given future is completed.
+func (a *App) Await(t *testing.T, f Future) (any, error) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !f.IsCompleted() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for the future to be completed")
+		}
+		a.commands.Execute(a)
+		time.Sleep(time.Millisecond)
+	}
+	return f.Wait()
+}
+
+// NewTestImage creates a blank image with the given size that is never drawn.
+func NewTestImage(size Size) *Image {
+	return &Image{raw: &rl.Image{Width: int32(size.W), Height: int32(size.H)}}
+}
+
+// GlobalString returns the value of a global variable of the script as a string, or "nil" if the
+// variable is not set. The script must not be running any function.
+func (s *Script) GlobalString(name string) string {
+	s.l.Global(name)
+	defer s.l.Pop(1)
+	if s.l.IsNil(-1) {
+		return "nil"
+	}
+	val, _ := s.l.ToString(-1)
+	return val
+}
+
+// NewTestRoom creates a room as large as the viewport with the given walk boxes.
+func NewTestRoom(walkboxes ...*WalkBox) *Room {
+	room := NewRoom(NewTestImage(NewSize(ScreenWidth, ViewportHeight)))
+	if len(walkboxes) > 0 {
+		room.walkboxes = NewWalkBoxMatrix(walkboxes)
+	}
+	return room
+}
+
+// Detour returns the point the actor walks through instead of the given waypoint when other
+// actors stand on it, in the way to the next one.
+func (a *Actor) Detour(waypoint, next Position) (Position, bool) {
+	p, ok := detour(a.pos, waypoint.ToPosf(), next.ToPosf(), a.obstacles(), a.walkable)
+	return p.ToPos(), ok
+}
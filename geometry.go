@@ -0,0 +1,149 @@
+package pctk
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// RoomGeometry is the geometry of a room: its walkable areas, the hotspots of its objects and the
+// positions where actors use them. It is typically imported from a map editor, where the artists
+// draw the shapes over the room background.
+type RoomGeometry struct {
+	WalkBoxes    []*WalkBox           // The walk boxes of the room
+	Hotspots     map[string]Rectangle // The hotspots of the room objects, indexed by object ID
+	UsePositions map[string]Position  // The use positions of the room objects, indexed by object ID
+}
+
+// NewRoomGeometry creates a new empty room geometry.
+func NewRoomGeometry() *RoomGeometry {
+	return &RoomGeometry{
+		Hotspots:     make(map[string]Rectangle),
+		UsePositions: make(map[string]Position),
+	}
+}
+
+// BinaryEncode encodes the room geometry to a binary format. The format is as follows:
+// - uint16: the number of walk boxes.
+// - for each walk box: the walk box (see WalkBox.BinaryEncode).
+// - uint16: the number of hotspots.
+// - for each hotspot:
+//   - string: the object ID.
+//   - int32: the X, Y, width and height of the hotspot.
+//
+// - uint16: the number of use positions.
+// - for each use position:
+//   - string: the object ID.
+//   - int32: the X and Y coordinates of the position.
+func (g *RoomGeometry) BinaryEncode(w io.Writer) (n int, err error) {
+	n, err = BinaryEncode(w, uint16(len(g.WalkBoxes)))
+	if err != nil {
+		return n, err
+	}
+	for _, walkbox := range g.WalkBoxes {
+		nn, err := BinaryEncode(w, walkbox)
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+
+	nn, err := BinaryEncode(w, uint16(len(g.Hotspots)))
+	n += nn
+	if err != nil {
+		return n, err
+	}
+	for _, id := range sortedKeys(g.Hotspots) {
+		r := g.Hotspots[id]
+		nn, err := BinaryEncode(w, id, int32(r.Pos.X), int32(r.Pos.Y), int32(r.Size.W), int32(r.Size.H))
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+
+	nn, err = BinaryEncode(w, uint16(len(g.UsePositions)))
+	n += nn
+	if err != nil {
+		return n, err
+	}
+	for _, id := range sortedKeys(g.UsePositions) {
+		p := g.UsePositions[id]
+		nn, err := BinaryEncode(w, id, int32(p.X), int32(p.Y))
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// BinaryDecode decodes the room geometry from a binary format. See BinaryEncode for the format.
+func (g *RoomGeometry) BinaryDecode(r io.Reader) error {
+	var count uint16
+	if err := BinaryDecode(r, &count); err != nil {
+		return err
+	}
+	g.WalkBoxes = make([]*WalkBox, count)
+	for i := range g.WalkBoxes {
+		g.WalkBoxes[i] = new(WalkBox)
+		if err := BinaryDecode(r, g.WalkBoxes[i]); err != nil {
+			return err
+		}
+	}
+
+	if err := BinaryDecode(r, &count); err != nil {
+		return err
+	}
+	g.Hotspots = make(map[string]Rectangle, count)
+	for i := 0; i < int(count); i++ {
+		var id string
+		var x, y, w, h int32
+		if err := BinaryDecode(r, &id, &x, &y, &w, &h); err != nil {
+			return err
+		}
+		g.Hotspots[id] = NewRect(int(x), int(y), int(w), int(h))
+	}
+
+	if err := BinaryDecode(r, &count); err != nil {
+		return err
+	}
+	g.UsePositions = make(map[string]Position, count)
+	for i := 0; i < int(count); i++ {
+		var id string
+		var x, y int32
+		if err := BinaryDecode(r, &id, &x, &y); err != nil {
+			return err
+		}
+		g.UsePositions[id] = NewPos(int(x), int(y))
+	}
+	return nil
+}
+
+// Validate checks the consistency of the room geometry. It returns an error for each isolated or
+// unreachable walk box, and for each use position that is out of the walkable area.
+func (g *RoomGeometry) Validate() []error {
+	if len(g.WalkBoxes) == 0 {
+		return nil
+	}
+	walkboxes := NewWalkBoxMatrix(g.WalkBoxes)
+	errs := walkboxes.Validate()
+	for _, id := range sortedKeys(g.UsePositions) {
+		pos := g.UsePositions[id].ToPosf()
+		if !walkboxes.IsWalkable(&pos) {
+			errs = append(errs, fmt.Errorf(
+				"use position %v of object %s is out of the walkable area", g.UsePositions[id], id,
+			))
+		}
+	}
+	return errs
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
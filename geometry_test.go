@@ -0,0 +1,55 @@
+package pctk_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoomGeometryBinaryEncodeDecode(t *testing.T) {
+	geom := pctk.NewRoomGeometry()
+	geom.WalkBoxes = []*pctk.WalkBox{
+		pctk.NewWalkBox("street", []*pctk.Positionf{{0, 112}, {479, 112}, {479, 143}, {0, 143}}),
+		pctk.NewWalkBox("alley", []*pctk.Positionf{{140, 92}, {200, 92}, {230, 112}, {110, 112}}).
+			WithScale(0.7, 1).
+			WithEnabled(false).
+			WithZPlanes("lamp", "fence"),
+	}
+	geom.Hotspots["bucket"] = pctk.NewRect(250, 100, 20, 20)
+	geom.Hotspots["door"] = pctk.NewRect(10, 20, 30, 60)
+	geom.UsePositions["bucket"] = pctk.NewPos(240, 120)
+
+	var buf bytes.Buffer
+	_, err := pctk.BinaryEncode(&buf, geom)
+	require.NoError(t, err)
+
+	decoded := pctk.NewRoomGeometry()
+	require.NoError(t, pctk.BinaryDecode(&buf, decoded))
+	require.Len(t, decoded.WalkBoxes, 2)
+	for i, walkbox := range geom.WalkBoxes {
+		assert.Equal(t, walkbox.ID(), decoded.WalkBoxes[i].ID())
+		assert.Equal(t, walkbox.IsEnabled(), decoded.WalkBoxes[i].IsEnabled())
+		assert.Equal(t, walkbox.Vertices(), decoded.WalkBoxes[i].Vertices())
+		assert.Equal(t, walkbox.ScaleAt(100), decoded.WalkBoxes[i].ScaleAt(100))
+		assert.Equal(t, walkbox.ZPlanes(), decoded.WalkBoxes[i].ZPlanes())
+	}
+	assert.Equal(t, geom.Hotspots, decoded.Hotspots)
+	assert.Equal(t, geom.UsePositions, decoded.UsePositions)
+}
+
+func TestRoomGeometryValidate(t *testing.T) {
+	geom := pctk.NewRoomGeometry()
+	geom.WalkBoxes = []*pctk.WalkBox{
+		pctk.NewWalkBox("street", []*pctk.Positionf{{0, 112}, {479, 112}, {479, 143}, {0, 143}}),
+	}
+	geom.UsePositions["bucket"] = pctk.NewPos(240, 120)
+	geom.UsePositions["door"] = pctk.NewPos(20, 80)
+
+	errs := geom.Validate()
+
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "object door is out of the walkable area")
+}
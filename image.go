@@ -77,3 +77,16 @@ func (i *Image) Draw(pos Position, tint rl.Color) {
 	}
 	rl.DrawTexture(i.Texture(), int32(pos.X), int32(pos.Y), tint)
 }
+
+// DrawRect draws the given rectangle of the image on the screen, at the same position it has in
+// the image.
+func (i *Image) DrawRect(rect Rectangle, tint rl.Color) {
+	if i == nil {
+		return
+	}
+	rect = rect.Intersection(NewRect(0, 0, int(i.Width()), int(i.Height())))
+	if rect.Size == (Size{}) {
+		return
+	}
+	rl.DrawTextureRec(i.Texture(), rect.toRaylib(), rect.Pos.toRaylib(), tint)
+}
@@ -4,6 +4,7 @@ package pctk
 // by the room scripts.
 type Object struct {
 	classes   ObjectClass    // The classes the object belongs to as OR-ed bit flags
+	exit      *ObjectExit    // The exit the object leads to, or nil if it is not an exit
 	hotspot   Rectangle      // The hotspot of the object (for mouse interaction)
 	id        string         // The ID of the object
 	name      string         // The name of the object as seen by the player
@@ -31,6 +32,40 @@ func (o *Object) CurrentState() *ObjectState {
 	return o.states[o.state]
 }
 
+// Exit returns the exit the object leads to, or nil if the object is not an exit.
+func (o *Object) Exit() *ObjectExit {
+	return o.exit
+}
+
+// IsExitOpen returns true if actors can go through the exit of the object. This is, the object is
+// an exit and it is in the state the exit requires, if any.
+func (o *Object) IsExitOpen() bool {
+	if o.exit == nil {
+		return false
+	}
+	if o.exit.State == "" {
+		return true
+	}
+	st := o.CurrentState()
+	return st != nil && st.Name == o.exit.State
+}
+
+// SetState sets the current state of the object.
+func (o *Object) SetState(state int) {
+	o.state = state
+}
+
+// StateByName returns the index of the state of the object with the given name, or -1 if not
+// found.
+func (o *Object) StateByName(name string) int {
+	for i, st := range o.states {
+		if st.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // Draw renders the object in the viewport.
 func (o *Object) Draw() {
 	if !o.IsVisible() {
@@ -41,6 +76,11 @@ func (o *Object) Draw() {
 	}
 }
 
+// Hotspot returns the hotspot of the object.
+func (o *Object) Hotspot() Rectangle {
+	return o.hotspot
+}
+
 // ID returns the ID of the object.
 func (o *Object) ID() string {
 	return o.id
@@ -82,6 +122,14 @@ func (o *Object) UsePosition() (Position, Direction) {
 // ObjectState represents a state of an object.
 type ObjectState struct {
 	Anim *Animation // The animation while in this state.
+	Name string     // The name of the state, if any. Used to refer to it from scripts and exits.
+}
+
+// ObjectExit is the exit of a room an object leads to, such as a door or a path.
+type ObjectExit struct {
+	Room  string // The ID of the room the exit leads to
+	Entry string // The entry point of the room where actors appear, or empty if none
+	State string // The name of the state the object must be in to go through, or empty if always open
 }
 
 // ObjectClass represents a class of objects. Classes are aimed to be used as bit flags that can be
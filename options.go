@@ -13,7 +13,36 @@ func WithScreenZoom(zoom int32) AppOption {
 	return func(a *App) { a.screenZoom = zoom }
 }
 
+// WithDebugOverlay enables or disables the debug overlay, which draws the walk boxes, hotspots,
+// use positions and walking paths of the current room.
+func WithDebugOverlay(enabled bool) AppOption {
+	return func(a *App) { a.debug.enabled = enabled }
+}
+
+// WithDebugOverlayKey sets the key that toggles the debug overlay. Use 0 to disable the key.
+func WithDebugOverlayKey(key int32) AppOption {
+	return func(a *App) { a.debug.key = key }
+}
+
+// RunMode is the way actors move when they are asked to run.
+type RunMode int
+
+const (
+	// RunModeFast makes actors walk DefaultActorRunFactor times faster.
+	RunModeFast RunMode = iota
+	// RunModeJump makes actors jump straight to their destination.
+	RunModeJump
+)
+
+// WithRunMode sets the way actors move when they are asked to run, e.g. when the player
+// double-clicks to walk somewhere.
+func WithRunMode(mode RunMode) AppOption {
+	return func(a *App) { a.runMode = mode }
+}
+
 var defaultAppOptions = []AppOption{
 	WithScreenCaption("Point&Click Toolkit"),
 	WithScreenZoom(4),
+	WithDebugOverlayKey(DefaultDebugOverlayKey),
+	WithRunMode(RunModeFast),
 }
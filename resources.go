@@ -109,6 +109,10 @@ type ResourceLoader interface {
 	// found.
 	LoadMusic(ref ResourceRef) *Music
 
+	// LoadRoomGeometry loads a room geometry from the given ref. It returns nil if the geometry
+	// is not found.
+	LoadRoomGeometry(ref ResourceRef) *RoomGeometry
+
 	// LoadScript loads a script from the given ref. It returns nil if the script is not found.
 	LoadScript(ref ResourceRef) *Script
 
@@ -127,6 +131,7 @@ type ResourceBundle struct {
 	costumes map[ResourceRef]*Costume
 	images   map[ResourceRef]*Image
 	music    map[ResourceRef]*Music
+	rooms    map[ResourceRef]*RoomGeometry
 	scripts  map[ResourceRef]*Script
 	sounds   map[ResourceRef]*Sound
 	sprites  map[ResourceRef]*SpriteSheet
@@ -138,6 +143,7 @@ func NewResourceBundle() *ResourceBundle {
 		costumes: make(map[ResourceRef]*Costume),
 		images:   make(map[ResourceRef]*Image),
 		music:    make(map[ResourceRef]*Music),
+		rooms:    make(map[ResourceRef]*RoomGeometry),
 		scripts:  make(map[ResourceRef]*Script),
 		sounds:   make(map[ResourceRef]*Sound),
 		sprites:  make(map[ResourceRef]*SpriteSheet),
@@ -159,6 +165,11 @@ func (c *ResourceBundle) PutMusic(ref ResourceRef, m *Music) {
 	c.music[ref] = m
 }
 
+// PutRoomGeometry adds a room geometry to the bundle.
+func (c *ResourceBundle) PutRoomGeometry(ref ResourceRef, g *RoomGeometry) {
+	c.rooms[ref] = g
+}
+
 // PutScript adds a script to the bundle.
 func (c *ResourceBundle) PutScript(ref ResourceRef, s *Script) {
 	c.scripts[ref] = s
@@ -189,6 +200,12 @@ func (c *ResourceBundle) LoadMusic(ref ResourceRef) *Music {
 	return c.music[ref]
 }
 
+// LoadRoomGeometry loads a room geometry from the given ref. It returns nil if the geometry is not
+// found.
+func (c *ResourceBundle) LoadRoomGeometry(ref ResourceRef) *RoomGeometry {
+	return c.rooms[ref]
+}
+
 // LoadScript loads a script from the given ref. It returns nil if the script is not found.
 func (c *ResourceBundle) LoadScript(ref ResourceRef) *Script {
 	return c.scripts[ref]
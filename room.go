@@ -7,11 +7,43 @@ import (
 
 // Room represents a room in the game.
 type Room struct {
-	actors     []*Actor  // The actors in the room
-	background *Image    // The background image of the room
-	id         string    // The ID of the room
-	objects    []*Object // The objects declared in the room
-	script     *Script   // The script where this room is defined. Used to call the room functions.
+	actors     []*Actor             // The actors in the room
+	background *Image               // The background image of the room
+	entries    map[string]RoomEntry // The entry points of the room, indexed by name
+	geometry   *RoomGeometry        // The geometry imported for the room, or nil if not imported
+	id         string               // The ID of the room
+	objects    []*Object            // The objects declared in the room
+	script     *Script              // The script where this room is defined. Used to call the room functions.
+	walkboxes  *WalkBoxMatrix       // The walkable areas of the room, or nil if actors can walk freely
+	transition Transition           // The transition shown by default when the room is shown
+	zplanes    []*ZPlane            // The foreground layers of the room
+}
+
+// RoomEntry is a named place where actors appear when they enter the room, such as the threshold
+// of a door.
+type RoomEntry struct {
+	Pos Position
+	Dir Direction
+}
+
+// ZPlane is a foreground layer of a room, such as a pillar or a counter. It is an image as large as
+// the room background, transparent except for the scenery that occludes the actors behind it.
+type ZPlane struct {
+	id       string
+	image    *Image
+	baseline int
+}
+
+// NewZPlane creates a new z-plane with the given ID and image. The actors standing above the
+// baseline are behind the plane, unless their walk box declares the planes that apply to it. Use
+// zero baseline for planes that only apply to walk boxes.
+func NewZPlane(id string, image *Image, baseline int) *ZPlane {
+	return &ZPlane{id: id, image: image, baseline: baseline}
+}
+
+// ID returns the ID of the z-plane.
+func (z *ZPlane) ID() string {
+	return z.id
 }
 
 // NewRoom creates a new room with the given background image.
@@ -39,6 +71,11 @@ func (r *Room) DeclareObject(obj *Object) {
 	r.objects = append(r.objects, obj)
 }
 
+// Size returns the size of the room, which is the size of its background.
+func (r *Room) Size() Size {
+	return NewSize(int(r.background.Width()), int(r.background.Height()))
+}
+
 // Draw renders the room in the viewport.
 func (r *Room) Draw() {
 	r.background.Draw(NewPos(0, 0), White)
@@ -54,10 +91,76 @@ func (r *Room) Draw() {
 	})
 	for _, item := range items {
 		item.Draw()
+		if actor, ok := item.(*Actor); ok {
+			r.drawZPlanesOver(actor)
+		}
+	}
+}
+
+// drawZPlanesOver draws the part of the z-planes that occlude the given actor.
+func (r *Room) drawZPlanesOver(actor *Actor) {
+	if len(r.zplanes) == 0 {
+		return
+	}
+	var declared []string
+	if r.walkboxes != nil {
+		declared = r.walkboxes.ZPlanesAt(&actor.pos)
+	}
+	rect := actor.drawRect()
+	for _, plane := range r.zplanes {
+		behind := actor.pos.Y < float32(plane.baseline)
+		if declared != nil {
+			behind = slices.Contains(declared, plane.id)
+		}
+		if behind {
+			plane.image.DrawRect(rect, White)
+		}
 	}
 }
 
-// ItemAt returns the item at the given position in the room.
+// Entry returns the entry point of the room with the given name.
+func (r *Room) Entry(name string) (RoomEntry, bool) {
+	entry, ok := r.entries[name]
+	return entry, ok
+}
+
+// FindPath returns the waypoints an actor has to go through to walk from one position to another
+// in the room. The path honors the walk boxes of the room, if any. If the destination is outside
+// the walkable area, the path ends in the closest reachable position.
+func (r *Room) FindPath(from, to Position) []Position {
+	if r == nil || r.walkboxes == nil {
+		return []Position{to}
+	}
+	src, dst := from.ToPosf(), to.ToPosf()
+	waypoints := r.walkboxes.FindPath(&src, &dst)
+	path := make([]Position, 0, len(waypoints))
+	for _, wp := range waypoints {
+		path = append(path, wp.ToPos())
+	}
+	return path
+}
+
+// DepthAt returns the scale and the speed factor of an actor standing in the given position of
+// the room, according to its walk boxes. Rooms with no walk boxes have no depth, so both values
+// are 1.
+func (r *Room) DepthAt(pos Positionf) (scale, speed float32) {
+	if r == nil || r.walkboxes == nil {
+		return 1, 1
+	}
+	return r.walkboxes.DepthAt(&pos)
+}
+
+// IsWalkable returns true if the given position is in the walkable area of the room. Rooms with no
+// walk boxes are walkable everywhere.
+func (r *Room) IsWalkable(pos Position) bool {
+	if r == nil || r.walkboxes == nil {
+		return true
+	}
+	p := pos.ToPosf()
+	return r.walkboxes.IsWalkable(&p)
+}
+
+// ItemAt returns the item at the given position in room coordinates.
 func (r *Room) ItemAt(pos Position) RoomItem {
 	if r == nil {
 		return nil
@@ -85,8 +188,11 @@ func (r *Room) ObjectByID(id string) *Object {
 	return nil
 }
 
-// PutActor puts an actor in the room.
+// PutActor puts an actor in the room, removing it from the room it was in before, if any.
 func (r *Room) PutActor(actor *Actor) {
+	if prev := actor.room; prev != nil && prev != r {
+		prev.RemoveActor(actor)
+	}
 	actor.room = r
 	for _, act := range r.actors {
 		if act == actor {
@@ -96,6 +202,14 @@ func (r *Room) PutActor(actor *Actor) {
 	r.actors = append(r.actors, actor)
 }
 
+// RemoveActor removes an actor from the room. It does nothing if the actor is not in the room.
+func (r *Room) RemoveActor(actor *Actor) {
+	r.actors = slices.DeleteFunc(r.actors, func(act *Actor) bool { return act == actor })
+	if actor.room == r {
+		actor.room = nil
+	}
+}
+
 // RoomItem is an item from a room that can be represented in the viewport.
 type RoomItem interface {
 	Class() ObjectClass
@@ -124,6 +238,7 @@ func (a *App) StartRoom(room *Room) {
 	for _, r := range a.rooms {
 		if r == room {
 			a.room = room
+			a.camera.Reset(room)
 			return
 		}
 	}
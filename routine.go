@@ -0,0 +1,122 @@
+package pctk
+
+import (
+	"log"
+	"time"
+)
+
+// RoutineStep is a step of the routine of an actor. It returns the action the actor does in the
+// step.
+type RoutineStep func(app *App, actor *Actor) *Action
+
+// RoutineWalkTo returns a routine step that makes the actor walk to the given position.
+func RoutineWalkTo(pos Position) RoutineStep {
+	return func(app *App, actor *Actor) *Action {
+		return pathTo(pos, WalkingPath)
+	}
+}
+
+// RoutineWait returns a routine step that makes the actor stand still for the given duration.
+func RoutineWait(d time.Duration) RoutineStep {
+	return func(app *App, actor *Actor) *Action {
+		return Waiting(d)
+	}
+}
+
+// RoutineSay returns a routine step that makes the actor say the given text.
+func RoutineSay(text string) RoutineStep {
+	return func(app *App, actor *Actor) *Action {
+		return Speaking(app, text, actor.TalkColor)
+	}
+}
+
+// RoutineFace returns a routine step that makes the actor turn to the given direction.
+func RoutineFace(dir Direction) RoutineStep {
+	return func(app *App, actor *Actor) *Action {
+		return Turning(dir)
+	}
+}
+
+// RoutineAnimate returns a routine step that makes the actor play once the animation of the
+// custom action of its costume with the given name. The step is skipped if there is no such
+// action, with a warning logged only the first time.
+func RoutineAnimate(name string) RoutineStep {
+	warned := false
+	return func(app *App, actor *Actor) *Action {
+		var act CostumeAction
+		ok := false
+		if actor.costume != nil {
+			act, ok = actor.costume.CustomAction(name)
+		}
+		if !ok {
+			if !warned {
+				log.Printf("Warning: actor %s has no action %s for its routine", actor.id, name)
+				warned = true
+			}
+			return Waiting(0)
+		}
+		return Animating(act, false)
+	}
+}
+
+// actorRoutine is the progress of the routine of an actor.
+type actorRoutine struct {
+	app     *App
+	steps   []RoutineStep
+	next    int     // The step to do next
+	current *Action // The action of the step being done, if any
+	paused  int     // The number of reasons the routine is paused for
+}
+
+// SetRoutine sets the routine of the actor: the steps it does over and over while it has nothing
+// else to do. Any other action interrupts the routine, which goes on with the interrupted step
+// afterwards. Idle actions, such as standing or following another actor, hold the routine until
+// they are stopped or replaced. Use nil steps to remove the routine.
+func (a *Actor) SetRoutine(app *App, steps []RoutineStep) {
+	if r := a.routine; r != nil && r.current != nil && r.current == a.act {
+		a.CancelAction()
+	}
+	a.routine = nil
+	if len(steps) > 0 {
+		a.routine = &actorRoutine{app: app, steps: steps}
+	}
+}
+
+// pauseRoutine stops the routine of the actor until resumeRoutine is called. The step being done
+// is interrupted.
+func (a *Actor) pauseRoutine() {
+	r := a.routine
+	if r == nil {
+		return
+	}
+	r.paused++
+	if r.current != nil && r.current == a.act {
+		a.CancelAction()
+	}
+}
+
+// resumeRoutine resumes the routine of the actor after it was paused.
+func (a *Actor) resumeRoutine() {
+	if r := a.routine; r != nil && r.paused > 0 {
+		r.paused--
+	}
+}
+
+// nextRoutineAction returns the action of the next step of the routine, or nil if there is
+// nothing to do. Steps that were interrupted are done again.
+func (a *Actor) nextRoutineAction() *Action {
+	r := a.routine
+	if r == nil || r.paused > 0 {
+		return nil
+	}
+	if r.current != nil {
+		if !r.current.Done().IsCompleted() {
+			return nil
+		}
+		if _, err := r.current.Done().Wait(); err == nil {
+			r.next = (r.next + 1) % len(r.steps)
+		}
+	}
+	r.current = r.steps[r.next](r.app, a)
+	return r.current
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"fmt"
 	"log"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/Shopify/go-lua"
@@ -20,9 +22,41 @@ func (s *Script) luaInit(app *App) {
 			s.l.PushGoFunction(f.Function)
 			s.l.SetGlobal(f.Name)
 		}
+		s.luaCameraApi(app)
+		s.l.SetGlobal("camera")
 	}
 }
 
+// luaCameraApi pushes the camera object, which has the methods to move the camera.
+func (s *Script) luaCameraApi(app *App) {
+	camera := withNewLuaObject(s.l, "camera")
+	camera.SetFunction("follow", lua.Function(func(l *lua.State) int {
+		withLuaTableAtIndex(l, 1).CheckObjectType("camera")
+		var cmd CameraFollow
+		if !l.IsNoneOrNil(2) {
+			cmd.Actor = withLuaTableAtIndex(l, 2).CheckObjectType("actor").GetActorByID(app, "id")
+		}
+		luaPushFuture(l, app.RunCommand(cmd))
+		return 1
+	}))
+	camera.SetFunction("panto", lua.Function(func(l *lua.State) int {
+		withLuaTableAtIndex(l, 1).CheckObjectType("camera")
+		cmd := CameraPanTo{
+			Position: luaCheckPosition(l, 2),
+		}
+		luaPushFuture(l, app.RunCommand(cmd))
+		return 1
+	}))
+	camera.SetFunction("setpos", lua.Function(func(l *lua.State) int {
+		withLuaTableAtIndex(l, 1).CheckObjectType("camera")
+		cmd := CameraSetPosition{
+			Position: luaCheckPosition(l, 2),
+		}
+		luaPushFuture(l, app.RunCommand(cmd))
+		return 1
+	}))
+}
+
 func (s *Script) luaRun(app *App, prom *Promise) {
 	go func() {
 		if s.l == nil {
@@ -69,12 +103,16 @@ func (s *Script) declareGlobalObjects(app *App) {
 			return
 		}
 
-		switch typ {
-		case "actor":
-			s.declareActor(app, key, obj)
-		case "room":
-			s.declareRoom(app, key, obj)
-		}
+		// The declaration is read from the wrapped table, since the methods of the object hide the
+		// declared fields with the same name, such as the costume of an actor.
+		obj.WithWrapped(func(decl luaTableUtils) {
+			switch typ {
+			case "actor":
+				s.declareActor(app, key, decl)
+			case "room":
+				s.declareRoom(app, key, decl)
+			}
+		})
 	})
 }
 
@@ -84,7 +122,10 @@ func (s *Script) declareActor(app *App, actorID string, actor luaTableUtils) {
 		ActorName: actor.GetString("name"),
 		Costume:   actor.GetRefOpt("costume", ResourceRefNull),
 		ScriptLoc: WithField(actorID),
+		Footprint: actor.GetSizeOpt("footprint", Size{}),
+		Routine:   actor.GetRoutineOpt("routine", nil),
 		Size:      actor.GetSizeOpt("size", DefaultActorSize),
+		Speed:     actor.GetSpeedOpt("speed", DefaultActorSpeed),
 		TalkColor: actor.GetColorOpt("talkcolor", DefaultActorTalkColor),
 		UsePos:    actor.GetPositionOpt("usepos", DefaultActorUsePos),
 		UseDir:    actor.GetDirectionOpt("usedir", DefaultActorDirection),
@@ -92,10 +133,59 @@ func (s *Script) declareActor(app *App, actorID string, actor luaTableUtils) {
 }
 
 func (s *Script) declareRoom(app *App, roomID string, room luaTableUtils) {
+	var walkboxes []*WalkBox
+	room.IfTableFieldExists("walkboxes", func(boxes luaTableUtils) {
+		boxes.ForEach(func(key int, value int) {
+			walkbox, err := luaCheckWalkBox(s.l, lua.CheckString(s.l, key), value)
+			if err != nil {
+				// Invalid walk boxes are skipped, as the room can still be used without them.
+				log.Printf("Warning: room %s: %v", roomID, err)
+				return
+			}
+			walkboxes = append(walkboxes, walkbox)
+		})
+	})
+	// Lua tables have no order. Sort the walk boxes to have the same matrix in every run.
+	slices.SortFunc(walkboxes, func(a, b *WalkBox) int {
+		return strings.Compare(a.ID(), b.ID())
+	})
+
+	entries := make(map[string]RoomEntry)
+	room.IfTableFieldExists("entries", func(ents luaTableUtils) {
+		ents.ForEach(func(key int, value int) {
+			entry := withLuaTableAtIndex(s.l, value)
+			entries[lua.CheckString(s.l, key)] = RoomEntry{
+				Pos: entry.GetPosition("pos"),
+				Dir: entry.GetDirectionOpt("dir", DefaultActorDirection),
+			}
+		})
+	})
+
+	var zplanes []RoomZPlane
+	room.IfTableFieldExists("zplanes", func(planes luaTableUtils) {
+		planes.ForEach(func(key int, value int) {
+			plane := withLuaTableAtIndex(s.l, value)
+			zplanes = append(zplanes, RoomZPlane{
+				ID:       lua.CheckString(s.l, key),
+				ImageRef: plane.GetRef("image"),
+				Baseline: plane.GetIntegerOpt("baseline", 0),
+			})
+		})
+	})
+	// Z-planes are drawn in order, so they are sorted as walk boxes are.
+	slices.SortFunc(zplanes, func(a, b RoomZPlane) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
 	app.RunCommand(RoomDeclare{
 		RoomID:        roomID,
 		Script:        s,
 		BackgroundRef: room.GetRef("background"),
+		Entries:       entries,
+		GeometryRef:   room.GetRefOpt("geometry", ResourceRefNull),
+		Transition:    room.GetTransitionOpt("transition", Transition{}),
+		WalkBoxes:     walkboxes,
+		ZPlanes:       zplanes,
 	}).Wait()
 
 	room.IfTableFieldExists("objects", func(objs luaTableUtils) {
@@ -109,7 +199,7 @@ func (s *Script) declareRoom(app *App, roomID string, room luaTableUtils) {
 
 			cmd := ObjectDeclare{
 				Class:     obj.GetClassOpt("class", 0),
-				Hotspot:   obj.GetRectangle("hotspot"),
+				Hotspot:   obj.GetRectangleOpt("hotspot", Rectangle{}),
 				Name:      lua.CheckString(s.l, key),
 				ObjectID:  objID,
 				Pos:       obj.GetPositionOpt("pos", NewPos(0, 0)),
@@ -117,15 +207,48 @@ func (s *Script) declareRoom(app *App, roomID string, room luaTableUtils) {
 				ScriptLoc: WithField(roomID, "objects", objID),
 				Sprites:   obj.GetRefOpt("sprites", ResourceRefNull),
 				UseDir:    obj.GetDirection("usedir"),
-				UsePos:    obj.GetPosition("usepos"),
+				UsePos:    obj.GetPositionOpt("usepos", Position{}),
 			}
 			obj.IfTableFieldExists("states", func(states luaTableUtils) {
-				states.ForEach(func(_ int, value int) {
+				// Lua tables have no order. The states in the array part go first, followed by the
+				// ones with a string key sorted by key, which are named after it.
+				states.ForEachItem(func(_ int, value int) {
 					state := withLuaTableAtIndex(s.l, value)
 					cmd.States = append(cmd.States, &ObjectState{
 						Anim: state.GetAnimationOpt("anim", nil),
+						Name: state.GetStringOpt("name", ""),
 					})
 				})
+				var keys []string
+				states.ForEach(func(key int, _ int) {
+					if s.l.TypeOf(key) == lua.TypeString {
+						keys = append(keys, lua.CheckString(s.l, key))
+					}
+				})
+				slices.Sort(keys)
+				for _, key := range keys {
+					states.IfTableFieldExists(key, func(state luaTableUtils) {
+						cmd.States = append(cmd.States, &ObjectState{
+							Anim: state.GetAnimationOpt("anim", nil),
+							Name: state.GetStringOpt("name", key),
+						})
+					})
+				}
+			})
+			obj.getFieldOpt("state", lua.TypeNone, func() {
+				if s.l.IsNumber(-1) {
+					// States are numbered from 1 in Lua.
+					cmd.State = lua.CheckInteger(s.l, -1) - 1
+				} else {
+					cmd.StateName = lua.CheckString(s.l, -1)
+				}
+			})
+			obj.IfTableFieldExists("exit", func(exit luaTableUtils) {
+				cmd.Exit = &ObjectExit{
+					Room:  exit.GetString("room"),
+					Entry: exit.GetStringOpt("entry", ""),
+					State: exit.GetStringOpt("state", ""),
+				}
 			})
 			app.RunCommand(cmd).Wait()
 		})
@@ -158,6 +281,94 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 		{Name: "actor", Function: func(l *lua.State) int {
 			actor := withNewLuaObjectWrapping(l, 1, "actor")
 			actor.SetBoolean("included", s.including)
+			inspect := func(l *lua.State) ActorState {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorInspect{
+					Actor: self.GetActorByID(app, "id"),
+				}
+				state, _ := app.RunCommand(cmd).Wait()
+				return state.(ActorState)
+			}
+			actor.SetFunction("costume", lua.Function(func(l *lua.State) int {
+				if ref := inspect(l).Costume; ref == ResourceRefNull {
+					l.PushNil()
+				} else {
+					l.PushString(ref.String())
+				}
+				return 1
+			}))
+			actor.SetFunction("dir", lua.Function(func(l *lua.State) int {
+				l.PushInteger(int(inspect(l).Direction))
+				return 1
+			}))
+			actor.SetFunction("inventory", lua.Function(func(l *lua.State) int {
+				inventory := inspect(l).Inventory
+				l.CreateTable(len(inventory), 0)
+				for i, obj := range inventory {
+					luaPushValue(l, obj.ScriptLocation())
+					l.RawSetInt(-2, i+1)
+				}
+				return 1
+			}))
+			actor.SetFunction("isspeaking", lua.Function(func(l *lua.State) int {
+				l.PushBoolean(inspect(l).Speaking)
+				return 1
+			}))
+			actor.SetFunction("iswalking", lua.Function(func(l *lua.State) int {
+				l.PushBoolean(inspect(l).Walking)
+				return 1
+			}))
+			actor.SetFunction("pos", lua.Function(func(l *lua.State) int {
+				luaPushPosition(l, inspect(l).Position)
+				return 1
+			}))
+			actor.SetFunction("room", lua.Function(func(l *lua.State) int {
+				if room := inspect(l).Room; room == nil {
+					l.PushNil()
+				} else {
+					l.Global(room.id)
+				}
+				return 1
+			}))
+			actor.SetFunction("animate", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				opts := withLuaTableAtIndex(l, 2)
+				cmd := ActorAnimate{
+					Actor: self.GetActorByID(app, "id"),
+					Loop:  opts.GetBooleanOpt("loop", false),
+					Queue: opts.GetBooleanOpt("queue", false),
+				}
+				opts.getField("action", lua.TypeNone, func() {
+					if l.IsNumber(-1) {
+						cmd.Action = CostumeAction(lua.CheckInteger(l, -1))
+					} else {
+						cmd.Name = lua.CheckString(l, -1)
+					}
+				})
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("face", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				target := withLuaTableAtIndex(l, 2)
+				opts := withLuaTableAtIndex(l, 3)
+				cmd := ActorFace{
+					Actor: self.GetActorByID(app, "id"),
+					Queue: opts.GetBooleanOpt("queue", false),
+				}
+				switch typ, _ := target.ObjectType(); typ {
+				case "actor":
+					cmd.Item = target.GetActorByID(app, "id")
+				case "object":
+					cmd.Item = target.GetObjectByID(app, "room", "id")
+				default:
+					cmd.Position = luaCheckPosition(l, 2)
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
 			actor.SetFunction("say", lua.Function(func(l *lua.State) int {
 				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
 				text := lua.CheckString(l, 2)
@@ -166,6 +377,7 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 					Actor: self.GetActorByID(app, "id"),
 					Text:  text,
 					Delay: opts.GetDurationOpt("delay", DefaultActorSpeakDelay),
+					Queue: opts.GetBooleanOpt("queue", false),
 				}
 				done := app.RunCommand(cmd)
 				luaPushFuture(l, done)
@@ -182,6 +394,43 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 				app.RunCommand(cmd).Wait()
 				return 0
 			}))
+			actor.SetFunction("follow", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				target := withLuaTableAtIndex(l, 2).CheckObjectType("actor")
+				opts := withLuaTableAtIndex(l, 3)
+				cmd := ActorFollow{
+					Actor:       self.GetActorByID(app, "id"),
+					Target:      target.GetActorByID(app, "id"),
+					Distance:    opts.GetIntegerOpt("distance", DefaultActorFollowDistance),
+					AcrossRooms: opts.GetBooleanOpt("rooms", false),
+					Queue:       opts.GetBooleanOpt("queue", false),
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("hide", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorHide{
+					Actor: self.GetActorByID(app, "id"),
+				}
+				app.RunCommand(cmd).Wait()
+				return 0
+			}))
+			actor.SetFunction("moveto", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				opts := withLuaTableAtIndex(l, 2)
+				cmd := ActorMoveTo{
+					Actor:    self.GetActorByID(app, "id"),
+					Position: opts.GetPositionOpt("pos", DefaultActorPosition),
+					LookAt:   opts.GetDirectionOpt("dir", DefaultActorDirection),
+				}
+				opts.IfTableFieldExists("room", func(room luaTableUtils) {
+					cmd.Room = room.CheckObjectType("room").GetRoomByID(app, "id")
+				})
+				app.RunCommand(cmd).Wait()
+				return 0
+			}))
 			actor.SetFunction("select", lua.Function(func(l *lua.State) int {
 				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
 				cmd := ActorSelectEgo{
@@ -197,6 +446,16 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 				cmd := ActorStand{
 					Actor:     app.ActorByID(self.GetString("id")),
 					Direction: opts.GetDirectionOpt("dir", DefaultActorDirection),
+					Queue:     opts.GetBooleanOpt("queue", false),
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("stop", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorStop{
+					Actor: self.GetActorByID(app, "id"),
 				}
 				done := app.RunCommand(cmd)
 				luaPushFuture(l, done)
@@ -216,9 +475,52 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 			actor.SetFunction("walkto", lua.Function(func(l *lua.State) int {
 				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
 				pos := luaCheckPosition(l, 2)
+				opts := withLuaTableAtIndex(l, 3)
 				cmd := ActorWalkToPosition{
 					Actor:    self.GetActorByID(app, "id"),
 					Position: pos,
+					Run:      opts.GetBooleanOpt("run", false),
+					Queue:    opts.GetBooleanOpt("queue", false),
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("setcostume", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorSetCostume{
+					Actor:   self.GetActorByID(app, "id"),
+					Costume: ResourceRefNull,
+				}
+				switch {
+				case l.IsString(2):
+					cmd.Costume = luaCheckResourceRef(l, 2)
+				case l.IsTable(2):
+					cmd.Costume = withLuaTableAtIndex(l, 2).CheckObjectType("costume").GetRef("ref")
+				case !l.IsNoneOrNil(2):
+					lua.ArgumentError(l, 2, "costume reference or object expected")
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("setroutine", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorSetRoutine{
+					Actor: self.GetActorByID(app, "id"),
+				}
+				if !l.IsNoneOrNil(2) {
+					cmd.Routine = luaCheckRoutine(l, 2)
+				}
+				done := app.RunCommand(cmd)
+				luaPushFuture(l, done)
+				return 1
+			}))
+			actor.SetFunction("setspeed", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("actor")
+				cmd := ActorSetSpeed{
+					Actor: self.GetActorByID(app, "id"),
+					Speed: luaCheckSpeed(l, 2),
 				}
 				done := app.RunCommand(cmd)
 				luaPushFuture(l, done)
@@ -264,6 +566,20 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 				}
 				return 1
 			}))
+			obj.SetFunction("setstate", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("object")
+				cmd := ObjectSetState{
+					Object: self.GetObjectByID(app, "room", "id"),
+				}
+				if l.IsNumber(2) {
+					// States are numbered from 1 in Lua.
+					cmd.State = lua.CheckInteger(l, 2) - 1
+				} else {
+					cmd.Name = lua.CheckString(l, 2)
+				}
+				luaPushFuture(l, app.RunCommand(cmd))
+				return 1
+			}))
 			return 1
 		}},
 		{Name: "room", Function: func(l *lua.State) int {
@@ -271,8 +587,23 @@ func (s *Script) luaResourceApi(app *App) []lua.RegistryFunction {
 			room.SetBoolean("included", s.including)
 			room.SetFunction("show", lua.Function(func(l *lua.State) int {
 				self := withLuaTableAtIndex(l, 1).CheckObjectType("room")
+				opts := withLuaTableAtIndex(l, 2)
+				transition := opts.GetTransitionOpt("transition", Transition{})
+				transition.Duration = opts.GetDurationOpt("duration", transition.Duration)
 				done := app.RunCommand(RoomShow{
-					Room: self.GetRoomByID(app, "id"),
+					Room:       self.GetRoomByID(app, "id"),
+					Entry:      opts.GetStringOpt("entry", ""),
+					Transition: transition,
+				})
+				luaPushFuture(l, done)
+				return 1
+			}))
+			room.SetFunction("enablebox", lua.Function(func(l *lua.State) int {
+				self := withLuaTableAtIndex(l, 1).CheckObjectType("room")
+				done := app.RunCommand(RoomEnableWalkBox{
+					Room:      self.GetRoomByID(app, "id"),
+					WalkBoxID: lua.CheckString(l, 2),
+					Enabled:   l.ToBoolean(3),
 				})
 				luaPushFuture(l, done)
 				return 1
@@ -406,6 +737,11 @@ func luaDeclareConstants(l *lua.State) {
 		"DOWN":  func() { l.PushInteger(int(DirDown)) },
 		"LEFT":  func() { l.PushInteger(int(DirLeft)) },
 
+		"UPRIGHT":   func() { l.PushInteger(int(DirUpRight)) },
+		"DOWNRIGHT": func() { l.PushInteger(int(DirDownRight)) },
+		"DOWNLEFT":  func() { l.PushInteger(int(DirDownLeft)) },
+		"UPLEFT":    func() { l.PushInteger(int(DirUpLeft)) },
+
 		// Predefined classes
 		"PERSON":     func() { luaPushClass(l, ObjectClassPerson) },
 		"PICKABLE":   func() { luaPushClass(l, ObjectClassPickable) },
@@ -470,6 +806,121 @@ func luaCheckPosition(l *lua.State, index int) (pos Position) {
 	return
 }
 
+func luaCheckVertex(l *lua.State, index int) *Positionf {
+	tab := withLuaTableAtIndex(l, index)
+	if tab.HasField("x") {
+		return &Positionf{X: float32(tab.GetInteger("x")), Y: float32(tab.GetInteger("y"))}
+	}
+	// Short form: {x, y}
+	coords := tab.GetItems()
+	if len(coords) != 2 {
+		lua.ArgumentError(l, index, "vertex must be {x=?, y=?} or {x, y}")
+	}
+	return &Positionf{X: float32(coords[0]), Y: float32(coords[1])}
+}
+
+// luaCheckWalkBox reads the walk box at the given index. Errors in the shape or the scale are
+// returned instead of raised, so the caller can tell which room the walk box belongs to.
+func luaCheckWalkBox(l *lua.State, id string, index int) (*WalkBox, error) {
+	var vertices []*Positionf
+	tab := withLuaTableAtIndex(l, index)
+	tab.ForEachItem(func(_ int, value int) {
+		vertices = append(vertices, luaCheckVertex(l, value))
+	})
+	if err := ValidateWalkBox(id, vertices); err != nil {
+		return nil, err
+	}
+	walkbox := NewWalkBox(id, vertices).WithSpeed(tab.GetNumberOpt("speed", 1))
+	tab.IfTableFieldExists("zplanes", func(planes luaTableUtils) {
+		var ids []string
+		planes.ForEachItem(func(_ int, value int) {
+			ids = append(ids, lua.CheckString(l, value))
+		})
+		walkbox.WithZPlanes(ids...)
+	})
+
+	// The scale is either a number or a table with the scale at the top and bottom edges.
+	l.Field(tab.index, "scale")
+	defer l.Pop(1)
+	switch {
+	case l.IsNumber(-1):
+		scale := float32(lua.CheckNumber(l, -1))
+		walkbox.WithScale(scale, scale)
+	case l.IsTable(-1):
+		scale := withLuaTableAtIndex(l, -1)
+		walkbox.WithScale(scale.GetNumberOpt("top", 1), scale.GetNumberOpt("bottom", 1))
+	case !l.IsNil(-1):
+		return nil, fmt.Errorf("walkbox %s has an invalid scale", id)
+	}
+	return walkbox, nil
+}
+
+// luaCheckSpeed checks the speed of an actor at the given index. It is either a table with the
+// speed in pixels per second on each axis, or a number that multiplies the default actor speed.
+func luaCheckSpeed(l *lua.State, index int) (speed Positionf) {
+	switch {
+	case l.IsNumber(index):
+		speed = DefaultActorSpeed.Scale(float32(lua.CheckNumber(l, index)))
+	case l.IsTable(index):
+		tab := withLuaTableAtIndex(l, index)
+		speed.X = tab.GetNumberOpt("x", DefaultActorSpeed.X)
+		speed.Y = tab.GetNumberOpt("y", DefaultActorSpeed.Y)
+	default:
+		lua.ArgumentError(l, index, "speed must be a number or {x=?, y=?}")
+	}
+	if speed.X <= 0 || speed.Y <= 0 {
+		lua.ArgumentError(l, index, "speed must be positive")
+	}
+	return
+}
+
+// luaCheckTransition checks the room transition at the given index. It is either the name of the
+// transition or a table with the name and the duration in milliseconds, as {name="iris",
+// duration=500}.
+func luaCheckTransition(l *lua.State, index int) (transition Transition) {
+	var name string
+	if l.IsTable(index) {
+		tab := withLuaTableAtIndex(l, index)
+		name = tab.GetString("name")
+		transition.Duration = tab.GetDurationOpt("duration", 0)
+	} else {
+		name = lua.CheckString(l, index)
+	}
+	kind, ok := ParseTransitionKind(name)
+	if !ok {
+		lua.ArgumentError(l, index, fmt.Sprintf("unknown transition %s", name))
+	}
+	transition.Kind = kind
+	return
+}
+
+// luaCheckRoutine checks the routine of an actor at the given index. It is a list of steps, each
+// one being a table with one of the following fields: walkto (a position), wait (milliseconds),
+// say (a text), face (a direction) or animate (the name of a custom costume action).
+func luaCheckRoutine(l *lua.State, index int) (routine []RoutineStep) {
+	tab := withLuaTableAtIndex(l, index)
+	tab.ForEachItem(func(i int, value int) {
+		step := withLuaTableAtIndex(l, value)
+		switch {
+		case step.HasField("walkto"):
+			step.getField("walkto", lua.TypeTable, func() {
+				routine = append(routine, RoutineWalkTo(luaCheckVertex(l, -1).ToPos()))
+			})
+		case step.HasField("wait"):
+			routine = append(routine, RoutineWait(step.GetDuration("wait")))
+		case step.HasField("say"):
+			routine = append(routine, RoutineSay(step.GetString("say")))
+		case step.HasField("face"):
+			routine = append(routine, RoutineFace(step.GetDirection("face")))
+		case step.HasField("animate"):
+			routine = append(routine, RoutineAnimate(step.GetString("animate")))
+		default:
+			lua.ArgumentError(l, index, fmt.Sprintf("invalid step %d in routine", i))
+		}
+	})
+	return
+}
+
 func luaCheckSize(l *lua.State, index int) (size Size) {
 	tab := withLuaTableAtIndex(l, index)
 	size.W = tab.GetInteger("w")
@@ -523,6 +974,14 @@ func luaPushColor(l *lua.State, c Color) {
 	}
 }
 
+func luaPushPosition(l *lua.State, pos Position) {
+	l.NewTable()
+	l.PushInteger(pos.X)
+	l.SetField(-2, "x")
+	l.PushInteger(pos.Y)
+	l.SetField(-2, "y")
+}
+
 func luaPushFuture(l *lua.State, f Future) {
 	lua.NewLibrary(l, []lua.RegistryFunction{
 		{Name: "iscompleted", Function: func(l *lua.State) int {
@@ -569,6 +1028,8 @@ func luaPushField(l *lua.State, f FieldAccessor) error {
 
 func luaPushValue(l *lua.State, val any) {
 	switch v := val.(type) {
+	case nil:
+		l.PushNil()
 	case bool:
 		l.PushBoolean(v)
 	case int:
@@ -625,6 +1086,23 @@ func withNewLuaObjectWrapping(l *lua.State, index int, typ string) luaTableUtils
 	return withLuaTableAtIndex(l, -1)
 }
 
+// WithWrapped calls the given function with the table wrapped by an object created with
+// withNewLuaObjectWrapping. If the object wraps no table, the function is called with the object.
+func (t luaTableUtils) WithWrapped(then func(luaTableUtils)) {
+	if !t.l.MetaTable(t.index) {
+		then(t)
+		return
+	}
+	t.l.Field(-1, "__index")
+	t.l.Remove(-2)
+	defer t.l.Pop(1)
+	if !t.l.IsTable(-1) {
+		then(t)
+		return
+	}
+	then(withLuaTableAtIndex(t.l, -1))
+}
+
 func (t luaTableUtils) IfTableFieldExists(key string, then func(luaTableUtils)) {
 	t.l.Field(t.index, key)
 	defer t.l.Pop(1)
@@ -642,6 +1120,27 @@ func (t luaTableUtils) ForEach(then func(key int, value int)) {
 	}
 }
 
+func (t luaTableUtils) ForEachItem(then func(i int, value int)) {
+	for i := 1; i <= t.l.RawLength(t.index); i++ {
+		t.l.RawGetInt(t.index, i)
+		then(i, -1)
+		t.l.Pop(1)
+	}
+}
+
+func (t luaTableUtils) GetItems() (val []int) {
+	t.ForEachItem(func(_ int, value int) {
+		val = append(val, lua.CheckInteger(t.l, value))
+	})
+	return
+}
+
+func (t luaTableUtils) HasField(key string) bool {
+	t.l.Field(t.index, key)
+	defer t.l.Pop(1)
+	return !t.l.IsNil(-1)
+}
+
 func (t luaTableUtils) GetString(key string) (val string) {
 	t.getField(key, lua.TypeString, func() { val = lua.CheckString(t.l, -1) })
 	return
@@ -664,6 +1163,12 @@ func (t luaTableUtils) GetIntegerOpt(key string, def int) (val int) {
 	return
 }
 
+func (t luaTableUtils) GetNumberOpt(key string, def float32) (val float32) {
+	val = def
+	t.getFieldOpt(key, lua.TypeNumber, func() { val = float32(lua.CheckNumber(t.l, -1)) })
+	return
+}
+
 func (t luaTableUtils) GetIntegers(key string) (val []int) {
 	t.getField(key, lua.TypeTable, func() {
 		tab := withLuaTableAtIndex(t.l, -1)
@@ -679,6 +1184,12 @@ func (t luaTableUtils) GetBoolean(key string) (val bool) {
 	return
 }
 
+func (t luaTableUtils) GetBooleanOpt(key string, def bool) (val bool) {
+	val = def
+	t.getFieldOpt(key, lua.TypeBoolean, func() { val = t.l.ToBoolean(-1) })
+	return
+}
+
 func (t luaTableUtils) GetDuration(key string) (val time.Duration) {
 	t.getField(key, lua.TypeNumber, func() {
 		val = time.Duration(lua.CheckInteger(t.l, -1)) * time.Millisecond
@@ -760,6 +1271,24 @@ func (t luaTableUtils) GetSizeOpt(key string, def Size) (val Size) {
 	return
 }
 
+func (t luaTableUtils) GetSpeedOpt(key string, def Positionf) (val Positionf) {
+	val = def
+	t.getFieldOpt(key, lua.TypeNone, func() { val = luaCheckSpeed(t.l, -1) })
+	return
+}
+
+func (t luaTableUtils) GetRoutineOpt(key string, def []RoutineStep) (val []RoutineStep) {
+	val = def
+	t.getFieldOpt(key, lua.TypeTable, func() { val = luaCheckRoutine(t.l, -1) })
+	return
+}
+
+func (t luaTableUtils) GetTransitionOpt(key string, def Transition) (val Transition) {
+	val = def
+	t.getFieldOpt(key, lua.TypeNone, func() { val = luaCheckTransition(t.l, -1) })
+	return
+}
+
 func (t luaTableUtils) GetRectangle(key string) (val Rectangle) {
 	t.getField(key, lua.TypeTable, func() {
 		val = luaCheckRectangle(t.l, -1)
@@ -767,6 +1296,14 @@ func (t luaTableUtils) GetRectangle(key string) (val Rectangle) {
 	return
 }
 
+func (t luaTableUtils) GetRectangleOpt(key string, def Rectangle) (val Rectangle) {
+	val = def
+	t.getFieldOpt(key, lua.TypeTable, func() {
+		val = luaCheckRectangle(t.l, -1)
+	})
+	return
+}
+
 func (t luaTableUtils) GetAnimation(key string) (val *Animation) {
 	t.getField(key, lua.TypeTable, func() {
 		val = luaCheckAnimation(t.l, -1)
@@ -0,0 +1,146 @@
+package pctk_test
+
+import (
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runTestScript(t *testing.T, app *pctk.App, res *pctk.ResourceBundle, id, code string) *pctk.Script {
+	t.Helper()
+	ref := pctk.NewResourceRef("scripts", pctk.ResourceID(id))
+	script := pctk.NewScript(pctk.ScriptLua, []byte(code))
+	res.PutScript(ref, script)
+	_, err := app.Await(t, app.RunCommand(pctk.ScriptRun{ScriptRef: ref}))
+	require.NoError(t, err)
+	return script
+}
+
+func TestLuaActorQueries(t *testing.T) {
+	res := pctk.NewResourceBundle()
+	res.PutCostume(pctk.NewResourceRef("resources", "costumes/Guybrush"), pctk.NewCostume(nil))
+	app := pctk.NewTestApp(res)
+
+	script := runTestScript(t, app, res, "guybrush", `
+guybrush = actor {
+	name = "Guybrush",
+	costume = "resources:costumes/Guybrush",
+}
+
+function query()
+	costume = guybrush:costume()
+	walking = tostring(guybrush:iswalking())
+end
+`)
+	_, err := app.Await(t, script.Call(pctk.WithField("query"), nil, false))
+	require.NoError(t, err)
+
+	assert.Equal(t, "resources:costumes/Guybrush", script.GlobalString("costume"))
+	assert.Equal(t, "false", script.GlobalString("walking"))
+}
+
+func TestLuaObjectStates(t *testing.T) {
+	res := pctk.NewResourceBundle()
+	app := pctk.NewTestApp(res)
+
+	runTestScript(t, app, res, "dock", `
+dock = room {
+	background = "resources:backgrounds/Dock",
+	objects = {
+		door = object {
+			name = "door",
+			usedir = UP,
+			states = {
+				{ name = "broken" },
+				open = {},
+				closed = {},
+			},
+			state = "closed",
+		},
+		gate = object {
+			name = "gate",
+			usedir = UP,
+			states = {
+				open = {},
+				closed = {},
+			},
+		},
+	},
+}
+`)
+
+	door := app.FindObject("dock", "door")
+	assert.Equal(t, 0, door.StateByName("broken"))
+	assert.Equal(t, 1, door.StateByName("closed"))
+	assert.Equal(t, 2, door.StateByName("open"))
+	assert.Equal(t, "closed", door.CurrentState().Name)
+
+	// The states with a string key are sorted, so the initial state is always the same.
+	gate := app.FindObject("dock", "gate")
+	assert.Equal(t, "closed", gate.CurrentState().Name)
+}
+
+func TestLuaRoomEnterReceivesPreviousRoomAndEntry(t *testing.T) {
+	res := pctk.NewResourceBundle()
+	bg := pctk.NewTestImage(pctk.NewSize(pctk.ScreenWidth, pctk.ViewportHeight))
+	res.PutImage(pctk.NewResourceRef("resources", "backgrounds/Dock"), bg)
+	res.PutImage(pctk.NewResourceRef("resources", "backgrounds/Town"), bg)
+	app := pctk.NewTestApp(res)
+
+	// Each room is declared in its own script, which does not include the other one.
+	dockScript := runTestScript(t, app, res, "dock", `
+dock = room {
+	background = "resources:backgrounds/Dock",
+}
+
+function dock:enter(prev, entry)
+	dock_prev = tostring(prev)
+end
+`)
+	townScript := runTestScript(t, app, res, "town", `
+town = room {
+	background = "resources:backgrounds/Town",
+	entries = {
+		gate = { pos = {x=10, y=100}, dir = RIGHT },
+	},
+}
+
+function town:enter(prev, entry)
+	town_prev = prev
+	town_entry = entry
+end
+`)
+
+	_, err := app.Await(t, app.RunCommand(pctk.RoomShow{Room: app.FindRoom("dock")}))
+	require.NoError(t, err)
+	assert.Equal(t, "nil", dockScript.GlobalString("dock_prev"))
+
+	_, err = app.Await(t, app.RunCommand(pctk.RoomShow{Room: app.FindRoom("town"), Entry: "gate"}))
+	require.NoError(t, err)
+	assert.Equal(t, "dock", townScript.GlobalString("town_prev"))
+	assert.Equal(t, "gate", townScript.GlobalString("town_entry"))
+}
+
+func TestLuaRoomSkipsInvalidWalkBoxes(t *testing.T) {
+	res := pctk.NewResourceBundle()
+	bg := pctk.NewTestImage(pctk.NewSize(pctk.ScreenWidth, pctk.ViewportHeight))
+	res.PutImage(pctk.NewResourceRef("resources", "backgrounds/Dock"), bg)
+	app := pctk.NewTestApp(res)
+
+	runTestScript(t, app, res, "dock", `
+dock = room {
+	background = "resources:backgrounds/Dock",
+	walkboxes = {
+		pier = { {0, 100}, {100, 100}, {100, 140}, {0, 140} },
+		boat = { {200, 100}, {300, 100}, {250, 120}, {300, 140}, {200, 140} },
+	},
+}
+`)
+
+	dock := app.FindRoom("dock")
+	require.NotNil(t, dock)
+	assert.True(t, dock.IsWalkable(pctk.NewPos(50, 120)))
+	assert.False(t, dock.IsWalkable(pctk.NewPos(220, 120)))
+}
@@ -2,6 +2,7 @@ package pctk
 
 import (
 	"fmt"
+	"math"
 
 	rl "github.com/gen2brain/raylib-go/raylib"
 )
@@ -79,21 +80,26 @@ func (p Position) Distance(other Position) Size {
 	return s
 }
 
-// DirectionTo returns the direction from the current position to another.
+// DirectionTo returns the direction from the current position to another. Diagonal directions
+// are returned when both the horizontal and vertical distances are similar enough.
 func (p Position) DirectionTo(other Position) Direction {
 	dist := p.Distance(other)
-	if dist.W > dist.H {
-		// Horizontal direction
-		if other.X < p.X {
-			return DirLeft
-		}
-		return DirRight
-	} else {
-		// Vertical direction
-		if other.Y < p.Y {
-			return DirUp
-		}
-		return DirDown
+	horizontal, vertical := DirRight, DirDown
+	if other.X < p.X {
+		horizontal = DirLeft
+	}
+	if other.Y < p.Y {
+		vertical = DirUp
+	}
+
+	// The direction is diagonal if the angle with the main axis is above ~22.5 degrees.
+	switch {
+	case dist.W > dist.H && 5*dist.H < 2*dist.W:
+		return horizontal
+	case dist.W <= dist.H && 5*dist.W <= 2*dist.H:
+		return vertical
+	default:
+		return diagonalDirection(horizontal, vertical)
 	}
 }
 
@@ -192,6 +198,24 @@ func (p *Positionf) Equals(p1 *Positionf) bool {
 	return p.X == p1.X && p.Y == p1.Y
 }
 
+// DistanceTo returns the euclidean distance between p and p1.
+func (p *Positionf) DistanceTo(p1 *Positionf) float32 {
+	dx, dy := p1.X-p.X, p1.Y-p.Y
+	return float32(math.Sqrt(float64(dx*dx + dy*dy)))
+}
+
+// ClosestPointOnSegment returns the point of the line segment p1->p2 that is closest to p.
+func (p *Positionf) ClosestPointOnSegment(p1, p2 *Positionf) *Positionf {
+	dx, dy := p2.X-p1.X, p2.Y-p1.Y
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return &Positionf{p1.X, p1.Y}
+	}
+	t := ((p.X-p1.X)*dx + (p.Y-p1.Y)*dy) / length
+	t = max(0, min(1, t))
+	return &Positionf{p1.X + t*dx, p1.Y + t*dy}
+}
+
 // Size represents a 2D size.
 type Size struct {
 	W, H int
@@ -241,6 +265,23 @@ func (r Rectangle) toRaylib() rl.Rectangle {
 	)
 }
 
+// Center returns the position at the center of the rectangle.
+func (r Rectangle) Center() Position {
+	return r.Pos.Add(NewPos(r.Size.W/2, r.Size.H/2))
+}
+
+// Intersection returns the area shared by the two rectangles. If they do not overlap, the returned
+// rectangle has zero size.
+func (r Rectangle) Intersection(other Rectangle) Rectangle {
+	left, top := max(r.Pos.X, other.Pos.X), max(r.Pos.Y, other.Pos.Y)
+	right := min(r.Pos.X+r.Size.W, other.Pos.X+other.Size.W)
+	bottom := min(r.Pos.Y+r.Size.H, other.Pos.Y+other.Size.H)
+	if right <= left || bottom <= top {
+		return Rectangle{}
+	}
+	return NewRect(left, top, right-left, bottom-top)
+}
+
 // Contains returns true if the mouse is into the given rectangle.
 func (r Rectangle) Contains(pos Position) bool {
 	return rl.CheckCollisionPointRec(pos.toRaylib(), r.toRaylib())
@@ -259,14 +300,80 @@ func (d Direction) String() string {
 		return "Left"
 	case DirRight:
 		return "Right"
+	case DirUpRight:
+		return "UpRight"
+	case DirDownRight:
+		return "DownRight"
+	case DirDownLeft:
+		return "DownLeft"
+	case DirUpLeft:
+		return "UpLeft"
 	default:
 		return "Unknown"
 	}
 }
 
+// IsDiagonal returns true if the direction is one of the diagonal directions.
+func (d Direction) IsDiagonal() bool {
+	return d >= DirUpRight && d <= DirUpLeft
+}
+
+// Cardinals returns the horizontal and vertical components of a diagonal direction. For cardinal
+// directions, both components are the direction itself.
+func (d Direction) Cardinals() (horizontal, vertical Direction) {
+	switch d {
+	case DirUpRight:
+		return DirRight, DirUp
+	case DirDownRight:
+		return DirRight, DirDown
+	case DirDownLeft:
+		return DirLeft, DirDown
+	case DirUpLeft:
+		return DirLeft, DirUp
+	default:
+		return d, d
+	}
+}
+
+// Offset returns the offset of one unit step in the direction.
+func (d Direction) Offset() Position {
+	switch d {
+	case DirRight:
+		return Position{1, 0}
+	case DirLeft:
+		return Position{-1, 0}
+	case DirUp:
+		return Position{0, -1}
+	case DirDown:
+		return Position{0, 1}
+	}
+	if d.IsDiagonal() {
+		h, v := d.Cardinals()
+		return h.Offset().Add(v.Offset())
+	}
+	return Position{}
+}
+
 const (
 	DirRight Direction = iota
 	DirLeft
 	DirUp
 	DirDown
+	DirUpRight
+	DirDownRight
+	DirDownLeft
+	DirUpLeft
 )
+
+func diagonalDirection(horizontal, vertical Direction) Direction {
+	switch {
+	case horizontal == DirRight && vertical == DirUp:
+		return DirUpRight
+	case horizontal == DirRight:
+		return DirDownRight
+	case vertical == DirUp:
+		return DirUpLeft
+	default:
+		return DirDownLeft
+	}
+}
@@ -0,0 +1,53 @@
+package pctk_test
+
+import (
+	"testing"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPositionDirectionTo(t *testing.T) {
+	from := pctk.NewPos(100, 100)
+	testCases := []struct {
+		to       pctk.Position
+		expected pctk.Direction
+	}{
+		{pctk.NewPos(150, 100), pctk.DirRight},
+		{pctk.NewPos(50, 110), pctk.DirLeft},
+		{pctk.NewPos(95, 20), pctk.DirUp},
+		{pctk.NewPos(100, 150), pctk.DirDown},
+		{pctk.NewPos(150, 60), pctk.DirUpRight},
+		{pctk.NewPos(150, 150), pctk.DirDownRight},
+		{pctk.NewPos(60, 130), pctk.DirDownLeft},
+		{pctk.NewPos(70, 60), pctk.DirUpLeft},
+		{pctk.NewPos(100, 100), pctk.DirDown},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.expected.String(), func(t *testing.T) {
+			assert.Equal(t, testCase.expected, from.DirectionTo(testCase.to))
+		})
+	}
+}
+
+func TestDirectionCardinals(t *testing.T) {
+	h, v := pctk.DirUpLeft.Cardinals()
+	assert.Equal(t, pctk.DirLeft, h)
+	assert.Equal(t, pctk.DirUp, v)
+
+	h, v = pctk.DirRight.Cardinals()
+	assert.Equal(t, pctk.DirRight, h)
+	assert.Equal(t, pctk.DirRight, v)
+
+	assert.Equal(t, pctk.NewPos(1, 1), pctk.DirDownRight.Offset())
+	assert.Equal(t, pctk.NewPos(0, -1), pctk.DirUp.Offset())
+}
+
+func TestRectangleIntersection(t *testing.T) {
+	rect := pctk.NewRect(10, 10, 20, 20)
+
+	assert.Equal(t, pctk.NewRect(20, 15, 10, 15), rect.Intersection(pctk.NewRect(20, 15, 40, 40)))
+	assert.Equal(t, pctk.NewRect(10, 10, 5, 5), rect.Intersection(pctk.NewRect(0, 0, 15, 15)))
+	assert.Equal(t, rect, rect.Intersection(pctk.NewRect(0, 0, 100, 100)))
+	assert.Equal(t, pctk.Rectangle{}, rect.Intersection(pctk.NewRect(30, 10, 10, 10)))
+}
@@ -28,6 +28,12 @@ func (s *SpriteSheet) Release() {
 
 // DrawSprite draws a sprite from the sprite sheet at the given position.
 func (s *SpriteSheet) DrawSprite(col, row uint, pos Position, flip bool) {
+	s.DrawSpriteScaled(col, row, pos, flip, 1)
+}
+
+// DrawSpriteScaled draws a sprite from the sprite sheet at the given position, scaled by the
+// given factor.
+func (s *SpriteSheet) DrawSpriteScaled(col, row uint, pos Position, flip bool, scale float32) {
 	src := Rectangle{
 		Pos: Position{
 			int(s.frameSize.W) * int(col),
@@ -38,7 +44,11 @@ func (s *SpriteSheet) DrawSprite(col, row uint, pos Position, flip bool) {
 	if flip {
 		src.Size = src.Size.FlipH()
 	}
-	rl.DrawTextureRec(s.texture(), src.toRaylib(), pos.toRaylib(), rl.White)
+	dst := rl.NewRectangle(
+		float32(pos.X), float32(pos.Y),
+		float32(s.frameSize.W)*scale, float32(s.frameSize.H)*scale,
+	)
+	rl.DrawTexturePro(s.texture(), src.toRaylib(), dst, rl.Vector2{}, 0, rl.White)
 }
 
 // BinaryEncode encodes the sprite sheet to a binary format. The encoded format is:
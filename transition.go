@@ -0,0 +1,225 @@
+package pctk
+
+import (
+	"math"
+	"time"
+
+	rl "github.com/gen2brain/raylib-go/raylib"
+)
+
+// TransitionKind is the kind of effect shown when a room replaces another in the viewport.
+type TransitionKind byte
+
+const (
+	// TransitionDefault is the default transition of the room being shown.
+	TransitionDefault TransitionKind = iota
+
+	// TransitionCut replaces the room instantly.
+	TransitionCut
+
+	// TransitionFade fades the previous room to black and then the new room from black.
+	TransitionFade
+
+	// TransitionCrossfade fades the previous room into the new one.
+	TransitionCrossfade
+
+	// TransitionIris closes a circle on the ego in the previous room and opens it on the ego in the
+	// new room.
+	TransitionIris
+
+	// TransitionWipe reveals the new room from left to right over the previous one.
+	TransitionWipe
+)
+
+var (
+	// DefaultTransitionDuration is the duration of the transitions that set no duration.
+	DefaultTransitionDuration = 500 * time.Millisecond
+
+	transitionKindNames = map[string]TransitionKind{
+		"cut":       TransitionCut,
+		"fade":      TransitionFade,
+		"crossfade": TransitionCrossfade,
+		"iris":      TransitionIris,
+		"wipe":      TransitionWipe,
+	}
+)
+
+// ParseTransitionKind returns the transition kind with the given name, which is one of cut, fade,
+// crossfade, iris or wipe. It returns false if there is no such transition.
+func ParseTransitionKind(name string) (TransitionKind, bool) {
+	kind, ok := transitionKindNames[name]
+	return kind, ok
+}
+
+// Transition is an effect shown when a room replaces another in the viewport.
+type Transition struct {
+	Kind     TransitionKind
+	Duration time.Duration // The duration of the effect, or zero for the default duration
+}
+
+// Or returns the transition with the kind and duration not set taken from other.
+func (t Transition) Or(other Transition) Transition {
+	if t.Kind == TransitionDefault {
+		t.Kind = other.Kind
+	}
+	if t.Duration == 0 {
+		t.Duration = other.Duration
+	}
+	return t
+}
+
+// hides returns true if the transition hides the previous room before the new room is shown.
+// Otherwise the previous room is captured to be shown along with the new one.
+func (t Transition) hides() bool {
+	return t.Kind == TransitionFade || t.Kind == TransitionIris
+}
+
+// roomTransition is a transition in progress. A transition hides or captures the previous room,
+// then the room is replaced, and finally the transition shows the new room.
+type roomTransition struct {
+	Transition
+	showing  bool          // Whether the transition is showing the new room
+	start    time.Time     // The time the current phase started
+	snapshot *rl.Texture2D // The capture of the previous room, if any
+	done     *Promise      // Completed when the current phase is finished
+}
+
+// hideRoom begins the transition by hiding or capturing the current room. The returned future is
+// completed when the room can be replaced.
+func (a *App) hideRoom(t Transition) Future {
+	a.endTransition()
+	tr := &roomTransition{Transition: t, start: time.Now(), done: NewPromise()}
+	a.transition = tr
+	if t.Kind == TransitionCut {
+		a.endTransition()
+	}
+	return tr.done
+}
+
+// showRoom ends the transition by showing the current room. The returned future is completed when
+// the transition is finished.
+func (a *App) showRoom(t Transition) Future {
+	var snapshot *rl.Texture2D
+	if prev := a.transition; prev != nil {
+		snapshot, prev.snapshot = prev.snapshot, nil
+	}
+	a.endTransition()
+	tr := &roomTransition{
+		Transition: t,
+		showing:    true,
+		start:      time.Now(),
+		snapshot:   snapshot,
+		done:       NewPromise(),
+	}
+	a.transition = tr
+	if t.Kind == TransitionCut {
+		a.endTransition()
+	}
+	return tr.done
+}
+
+// endTransition finishes the transition in progress, if any.
+func (a *App) endTransition() {
+	tr := a.transition
+	if tr == nil {
+		return
+	}
+	if tr.snapshot != nil {
+		rl.UnloadTexture(*tr.snapshot)
+	}
+	if !tr.done.IsCompleted() {
+		tr.done.Complete()
+	}
+	a.transition = nil
+}
+
+// captureTransition captures the room drawn in the screen if the transition needs it.
+func (a *App) captureTransition() {
+	tr := a.transition
+	if tr == nil || tr.showing || tr.hides() || tr.done.IsCompleted() {
+		return
+	}
+	img := rl.LoadImageFromScreen()
+	zoom := float32(a.screenZoom)
+	rl.ImageCrop(img, rl.NewRectangle(0, 0, ScreenWidth*zoom, ViewportHeight*zoom))
+	tex := rl.LoadTextureFromImage(img)
+	rl.UnloadImage(img)
+	tr.snapshot = &tex
+	tr.done.Complete()
+}
+
+// drawTransition draws the transition in progress over the viewport.
+func (a *App) drawTransition() {
+	tr := a.transition
+	if tr == nil {
+		return
+	}
+	duration := tr.Duration
+	if tr.hides() {
+		duration /= 2
+	}
+	t := float32(1)
+	if duration > 0 {
+		t = min(1, float32(time.Since(tr.start))/float32(duration))
+	}
+
+	rl.BeginScissorMode(0, 0, ScreenWidth*a.screenZoom, ViewportHeight*a.screenZoom)
+	switch tr.Kind {
+	case TransitionFade:
+		if tr.showing {
+			t = 1 - t
+		}
+		rl.DrawRectangleRec(ViewportRect.toRaylib(), rl.Fade(rl.Black, t))
+	case TransitionIris:
+		if !tr.showing {
+			t = 1 - t
+		}
+		center := ViewportRect.Center()
+		if ego := a.ego; ego != nil && ego.room == a.room {
+			center = a.camera.ToViewport(ego.Hotspot().Center())
+		}
+		radius := irisRadius(center)
+		rl.DrawRing(center.toRaylib(), t*radius, radius+1, 0, 360, 64, rl.Black)
+	case TransitionCrossfade:
+		if tr.showing {
+			tr.drawPrevious(ViewportRect, 1-t, a.screenZoom)
+		}
+	case TransitionWipe:
+		if tr.showing {
+			x := int(t * ScreenWidth)
+			tr.drawPrevious(NewRect(x, 0, ScreenWidth-x, ViewportHeight), 1, a.screenZoom)
+		}
+	}
+	rl.EndScissorMode()
+
+	if time.Since(tr.start) >= duration && (tr.showing || tr.hides()) {
+		if tr.showing {
+			a.endTransition()
+		} else if !tr.done.IsCompleted() {
+			// Keep the room hidden until the new one is shown.
+			tr.done.Complete()
+		}
+	}
+}
+
+// drawPrevious draws the given rectangle of the previous room with the given opacity. If the
+// previous room was not captured, it is drawn black.
+func (tr *roomTransition) drawPrevious(rect Rectangle, alpha float32, zoom int32) {
+	if tr.snapshot == nil {
+		rl.DrawRectangleRec(rect.toRaylib(), rl.Fade(rl.Black, alpha))
+		return
+	}
+	z := float32(zoom)
+	src := rl.NewRectangle(
+		float32(rect.Pos.X)*z, float32(rect.Pos.Y)*z, float32(rect.Size.W)*z, float32(rect.Size.H)*z,
+	)
+	rl.DrawTexturePro(*tr.snapshot, src, rect.toRaylib(), rl.Vector2{}, 0, rl.Fade(rl.White, alpha))
+}
+
+// irisRadius returns the radius of an iris centered in the given position that shows the whole
+// viewport.
+func irisRadius(center Position) float32 {
+	w := float64(max(center.X, ScreenWidth-center.X))
+	h := float64(max(center.Y, ViewportHeight-center.Y))
+	return float32(math.Hypot(w, h))
+}
@@ -0,0 +1,32 @@
+package pctk_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apoloval/pctk"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTransitionKind(t *testing.T) {
+	kind, ok := pctk.ParseTransitionKind("iris")
+	assert.True(t, ok)
+	assert.Equal(t, pctk.TransitionIris, kind)
+
+	_, ok = pctk.ParseTransitionKind("spiral")
+	assert.False(t, ok)
+}
+
+func TestTransitionOr(t *testing.T) {
+	roomDefault := pctk.Transition{Kind: pctk.TransitionFade, Duration: time.Second}
+
+	assert.Equal(t, roomDefault, pctk.Transition{}.Or(roomDefault))
+	assert.Equal(t,
+		pctk.Transition{Kind: pctk.TransitionWipe, Duration: time.Second},
+		pctk.Transition{Kind: pctk.TransitionWipe}.Or(roomDefault),
+	)
+	assert.Equal(t,
+		pctk.Transition{Kind: pctk.TransitionFade, Duration: 200 * time.Millisecond},
+		pctk.Transition{Duration: 200 * time.Millisecond}.Or(roomDefault),
+	)
+}
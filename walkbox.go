@@ -1,37 +1,204 @@
 package pctk
 
-import "log"
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"slices"
+)
 
 // Walkbox refers to a convex polygonal area that defines the walkable space for actors.
 type WalkBox struct {
-	walkBoxID string
-	enabled   bool
-	vertices  [4]*Positionf
+	walkBoxID   string
+	enabled     bool
+	vertices    []*Positionf
+	scaleTop    float32  // The scale of the actors at the top edge of the WalkBox
+	scaleBottom float32  // The scale of the actors at the bottom edge of the WalkBox
+	speed       float32  // The factor applied to the speed of the actors walking in the WalkBox
+	zplanes     []string // The z-planes drawn over the actors standing in the WalkBox
 }
 
 // NewWalkBox creates a new WalkBox with the given ID and vertices.
-// It ensures the polygon formed by the vertices is convex. If not, it will cause a panic.
+// It ensures the polygon formed by the vertices is convex and it has at least 3 vertices. If not,
+// it will cause a panic. Use ValidateWalkBox to check the vertices in advance.
 // Why convex? Because you can draw a straight line/path between any two vertices inside the polygon
 // without needing to implement complex pathfinding algorithms.
-func NewWalkBox(id string, vertices [4]*Positionf) *WalkBox {
+func NewWalkBox(id string, vertices []*Positionf) *WalkBox {
 	w := &WalkBox{
-		walkBoxID: id,
-		vertices:  vertices,
-		enabled:   true,
+		walkBoxID:   id,
+		vertices:    vertices,
+		enabled:     true,
+		scaleTop:    1,
+		scaleBottom: 1,
+		speed:       1,
 	}
 
-	if !w.isConvex() {
-		log.Panicf("walkbox must be a convex polygon: %v", vertices)
+	if err := w.validate(); err != nil {
+		log.Panic(err)
 	}
 	return w
 }
 
-// isConvex check if the current WalkBox is a convex poligon.
+// ValidateWalkBox checks if the given vertices form a valid WalkBox, this is, a convex polygon with
+// at least 3 vertices. It returns an error describing the problem otherwise.
+func ValidateWalkBox(id string, vertices []*Positionf) error {
+	w := &WalkBox{walkBoxID: id, vertices: vertices}
+	return w.validate()
+}
+
+// ID returns the identifier of the WalkBox.
+func (w *WalkBox) ID() string {
+	return w.walkBoxID
+}
+
+// IsEnabled returns true if actors can walk through the WalkBox, false otherwise.
+func (w *WalkBox) IsEnabled() bool {
+	return w.enabled
+}
+
+// Vertices returns the vertices of the WalkBox.
+func (w *WalkBox) Vertices() []*Positionf {
+	return w.vertices
+}
+
+// WithEnabled sets whether actors can walk through the WalkBox.
+func (w *WalkBox) WithEnabled(enabled bool) *WalkBox {
+	w.enabled = enabled
+	return w
+}
+
+// WithScale sets the scale of the actors standing in the WalkBox. The scale is interpolated from
+// the top edge of the box to the bottom edge, so actors look smaller as they go far away.
+func (w *WalkBox) WithScale(top, bottom float32) *WalkBox {
+	w.scaleTop = top
+	w.scaleBottom = bottom
+	return w
+}
+
+// WithSpeed sets the factor applied to the speed of the actors walking through the WalkBox.
+func (w *WalkBox) WithSpeed(speed float32) *WalkBox {
+	w.speed = speed
+	return w
+}
+
+// WithZPlanes sets the IDs of the z-planes of the room that are drawn over the actors standing in
+// the WalkBox.
+func (w *WalkBox) WithZPlanes(ids ...string) *WalkBox {
+	w.zplanes = ids
+	return w
+}
+
+// ZPlanes returns the IDs of the z-planes of the room that are drawn over the actors standing in
+// the WalkBox.
+func (w *WalkBox) ZPlanes() []string {
+	return w.zplanes
+}
+
+// ScaleAt returns the scale of an actor standing in the given vertical coordinate of the WalkBox.
+func (w *WalkBox) ScaleAt(y float32) float32 {
+	top, bottom := w.vertices[0].Y, w.vertices[0].Y
+	for _, vertex := range w.vertices {
+		top = min(top, vertex.Y)
+		bottom = max(bottom, vertex.Y)
+	}
+	if bottom == top {
+		return w.scaleBottom
+	}
+	t := max(0, min(1, (y-top)/(bottom-top)))
+	return w.scaleTop + t*(w.scaleBottom-w.scaleTop)
+}
+
+// Speed returns the factor applied to the speed of the actors walking through the WalkBox.
+func (w *WalkBox) Speed() float32 {
+	return w.speed
+}
+
+// BinaryEncode encodes the WalkBox to a binary format. The format is as follows:
+// - string: the ID of the WalkBox.
+// - bool: whether the WalkBox is enabled.
+// - uint16: the number of vertices.
+// - for each vertex:
+//   - float32: the X coordinate.
+//   - float32: the Y coordinate.
+//
+// - float32: the scale at the top edge.
+// - float32: the scale at the bottom edge.
+// - float32: the speed factor.
+// - uint16: the number of z-planes.
+// - for each z-plane: string: the z-plane ID.
+func (w *WalkBox) BinaryEncode(wr io.Writer) (n int, err error) {
+	n, err = BinaryEncode(wr, w.walkBoxID, w.enabled, uint16(len(w.vertices)))
+	if err != nil {
+		return n, err
+	}
+	for _, vertex := range w.vertices {
+		nn, err := BinaryEncode(wr, vertex.X, vertex.Y)
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+	nn, err := BinaryEncode(wr, w.scaleTop, w.scaleBottom, w.speed, uint16(len(w.zplanes)))
+	n += nn
+	if err != nil {
+		return n, err
+	}
+	for _, id := range w.zplanes {
+		nn, err := BinaryEncode(wr, id)
+		n += nn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// BinaryDecode decodes the WalkBox from a binary format. See BinaryEncode for the format.
+func (w *WalkBox) BinaryDecode(r io.Reader) error {
+	var count uint16
+	if err := BinaryDecode(r, &w.walkBoxID, &w.enabled, &count); err != nil {
+		return err
+	}
+	w.vertices = make([]*Positionf, count)
+	for i := range w.vertices {
+		w.vertices[i] = new(Positionf)
+		if err := BinaryDecode(r, &w.vertices[i].X, &w.vertices[i].Y); err != nil {
+			return err
+		}
+	}
+	if err := BinaryDecode(r, &w.scaleTop, &w.scaleBottom, &w.speed, &count); err != nil {
+		return err
+	}
+	w.zplanes = nil
+	for i := 0; i < int(count); i++ {
+		var id string
+		if err := BinaryDecode(r, &id); err != nil {
+			return err
+		}
+		w.zplanes = append(w.zplanes, id)
+	}
+	return w.validate()
+}
+
+func (w *WalkBox) validate() error {
+	if len(w.vertices) < 3 {
+		return fmt.Errorf("walkbox %s must have at least 3 vertices: %v", w.walkBoxID, w.vertices)
+	}
+	if !w.isConvex() {
+		return fmt.Errorf("walkbox %s must be a convex polygon: %v", w.walkBoxID, w.vertices)
+	}
+	return nil
+}
+
+// isConvex check if the current WalkBox is a convex poligon. All the turns between consecutive
+// edges must be to the same side, and they must add up to a single full turn. Otherwise, the edges
+// of the polygon cross each other, as in a star.
 func (w *WalkBox) isConvex() bool {
 	numVertices := len(w.vertices)
 
-	var totalCrossProduct float32
-	var polygonDirection bool // true if clockwise, false if counter-clockwise
+	var direction int // The side of the turns, 1 if counter-clockwise, -1 if clockwise, 0 if unknown
+	var totalAngle float64
 	for i := 0; i < numVertices; i++ {
 		// Get three consecutive vertices (cyclically)
 		p1 := w.vertices[i]
@@ -39,22 +206,23 @@ func (w *WalkBox) isConvex() bool {
 		p3 := w.vertices[(i+2)%numVertices]
 
 		cp := p1.CrossProduct(p2, p3)
-
 		if cp == 0 {
 			continue // Skip collinear vertices
 		}
-
-		totalCrossProduct += cp
-
-		if i == 0 {
-			polygonDirection = cp > 0
-		} else {
-			if (cp > 0) != polygonDirection {
-				return false // If direction changes, the polygon is not convex
-			}
+		side := 1
+		if cp < 0 {
+			side = -1
 		}
+		if direction == 0 {
+			direction = side
+		} else if side != direction {
+			return false // If direction changes, the polygon is not convex
+		}
+
+		dot := (p2.X-p1.X)*(p3.X-p2.X) + (p2.Y-p1.Y)*(p3.Y-p2.Y)
+		totalAngle += math.Atan2(float64(cp), float64(dot))
 	}
-	return totalCrossProduct != 0
+	return direction != 0 && math.Abs(math.Abs(totalAngle)-2*math.Pi) < walkBoxAngleEpsilon
 }
 
 // ContainsPoint check if the provided position is in the boundaries defined by the WalkBox.
@@ -81,17 +249,19 @@ func (w *WalkBox) ContainsPoint(p *Positionf) bool {
 }
 
 // IsAdjacent checks if two WalkBoxes are adjacent. It returns false if either WalkBox is disabled.
+// Vertices lying on the boundary of the other WalkBox are considered to be shared, so boxes that
+// touch each other with no shared vertices are still adjacent.
 func (w *WalkBox) IsAdjacent(otherWalkBox *WalkBox) bool {
 	if w.enabled && otherWalkBox.enabled {
 		for _, vertex := range otherWalkBox.vertices {
-			if w.ContainsPoint(vertex) {
+			if _, d := w.closestPoint(vertex); d < walkBoxEpsilon {
 				return true
 			}
 		}
 
 		// two-way verification
 		for _, vertex := range w.vertices {
-			if otherWalkBox.ContainsPoint(vertex) {
+			if _, d := otherWalkBox.closestPoint(vertex); d < walkBoxEpsilon {
 				return true
 			}
 		}
@@ -100,6 +270,78 @@ func (w *WalkBox) IsAdjacent(otherWalkBox *WalkBox) bool {
 	return false
 }
 
+// closestPoint returns the point of the WalkBox that is closest to p, along with its distance to p.
+// If p is inside the WalkBox, p itself is returned with a distance of zero.
+func (w *WalkBox) closestPoint(p *Positionf) (*Positionf, float32) {
+	if w.ContainsPoint(p) {
+		return p, 0
+	}
+
+	var closest *Positionf
+	var distance float32
+	numVertices := len(w.vertices)
+	for i := 0; i < numVertices; i++ {
+		candidate := p.ClosestPointOnSegment(w.vertices[i], w.vertices[(i+1)%numVertices])
+		if d := p.DistanceTo(candidate); closest == nil || d < distance {
+			closest, distance = candidate, d
+		}
+	}
+	return closest, distance
+}
+
+// gate returns the segment shared by the boundaries of two adjacent WalkBoxes. This is the place
+// where an actor can cross from one box to the other. If the boxes only touch in one point, both
+// ends of the segment will be the same. If they are not adjacent, nil values are returned.
+func (w *WalkBox) gate(other *WalkBox) (*Positionf, *Positionf) {
+	var points []*Positionf
+	for _, vertex := range w.vertices {
+		if _, d := other.closestPoint(vertex); d < walkBoxEpsilon {
+			points = append(points, vertex)
+		}
+	}
+	for _, vertex := range other.vertices {
+		if _, d := w.closestPoint(vertex); d < walkBoxEpsilon {
+			points = append(points, vertex)
+		}
+	}
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	// The gate goes between the pair of points that are further from each other.
+	a, b := points[0], points[0]
+	var distance float32
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			if d := points[i].DistanceTo(points[j]); d > distance {
+				a, b, distance = points[i], points[j], d
+			}
+		}
+	}
+	return a, b
+}
+
+// center returns the geometric center of the WalkBox.
+func (w *WalkBox) center() *Positionf {
+	var center Positionf
+	for _, vertex := range w.vertices {
+		center = center.Add(*vertex)
+	}
+	center = center.Scale(1 / float32(len(w.vertices)))
+	return &center
+}
+
+// distanceTo returns the walking distance from the center of the WalkBox to the center of an
+// adjacent one through the middle of their shared gate.
+func (w *WalkBox) distanceTo(other *WalkBox) float32 {
+	a, b := w.gate(other)
+	if a == nil {
+		return infinityDistance
+	}
+	middle := a.Add(*b).Scale(0.5)
+	return w.center().DistanceTo(&middle) + middle.DistanceTo(other.center())
+}
+
 // WalkBoxMatrix represents a collection of WalkBoxes and their adjacency relationships.
 type WalkBoxMatrix struct {
 	walkBoxes       []*WalkBox
@@ -107,10 +349,17 @@ type WalkBoxMatrix struct {
 }
 
 const (
-	// infinityDistance represents a maximum distance value used for unconnected paths.
-	infinityDistance = 255
+	// infinityDistance represents the distance value used for unconnected paths.
+	infinityDistance = math.MaxFloat32
 	// InvalidWalkBox indicates an invalid WalkBox ID, typically used to signify non-existence.
 	InvalidWalkBox = -1
+
+	// walkBoxEpsilon is the tolerance used to consider a point lies on the boundary of a WalkBox.
+	walkBoxEpsilon = 0.01
+
+	// walkBoxAngleEpsilon is the tolerance, in radians, used to check that the edges of a WalkBox
+	// make a full turn.
+	walkBoxAngleEpsilon = 0.001
 )
 
 // NewWalkBoxMatrix creates and returns a new WalkBoxMatrix instance
@@ -123,25 +372,30 @@ func NewWalkBoxMatrix(walkboxes []*WalkBox) *WalkBoxMatrix {
 	return wm
 }
 
-// calculateItineraryMatrix computes the shortest paths between WalkBoxes and returns the resulting itinerary matrix.
+// resetItinerary computes the shortest paths between WalkBoxes and stores the resulting
+// itinerary matrix. For every pair of boxes (i, j), the itinerary matrix contains the next box to
+// visit in the way from i to j.
+//
+// The length of a route is the walking distance across the boxes: moving from a box to an
+// adjacent one costs the distance from the center of the first box to the middle of the shared
+// gate, plus the distance from there to the center of the second box.
 func (wm *WalkBoxMatrix) resetItinerary() {
 	numBoxes := len(wm.walkBoxes)
-	distanceMatrix := make([][]int, numBoxes)
+	distanceMatrix := make([][]float32, numBoxes)
 	itineraryMatrix := make([][]int, numBoxes)
 
 	for i, walkbox := range wm.walkBoxes {
 		itineraryMatrix[i] = make([]int, numBoxes)
-		distanceMatrix[i] = make([]int, numBoxes)
+		distanceMatrix[i] = make([]float32, numBoxes)
 
-		// Initialize the distance matrix: each box has distance 0 to itself,
-		// and distance 1 to its direct neighbors. Initially, it has distance
-		// 255 (= infinityDistance) to all other boxes.
+		// Initialize the distance matrix: each box has distance 0 to itself, the walking
+		// distance to its direct neighbors and infinite distance to all other boxes.
 		for j, otherWalkBox := range wm.walkBoxes {
 			if i == j {
 				distanceMatrix[i][j] = 0
 				itineraryMatrix[i][j] = i
 			} else if walkbox.IsAdjacent(otherWalkBox) {
-				distanceMatrix[i][j] = 1
+				distanceMatrix[i][j] = walkbox.distanceTo(otherWalkBox)
 				itineraryMatrix[i][j] = j
 			} else {
 				distanceMatrix[i][j] = infinityDistance
@@ -150,15 +404,20 @@ func (wm *WalkBoxMatrix) resetItinerary() {
 		}
 	}
 
-	// Compute the shortest routes between boxes via Kleene's algorithm.
-	for i := range wm.walkBoxes {
-		for j := range wm.walkBoxes {
-			for k := range wm.walkBoxes {
-				distIK := distanceMatrix[i][k]
-				distKJ := distanceMatrix[k][j]
-				if distanceMatrix[i][j] > distIK+distKJ {
-					distanceMatrix[i][j] = distIK + distKJ
-					itineraryMatrix[i][j] = k
+	// Compute the shortest routes between boxes via Floyd-Warshall algorithm. The intermediate
+	// box k must be the outermost loop, and the next box from i to j is the next box from i to k.
+	for k := range wm.walkBoxes {
+		for i := range wm.walkBoxes {
+			if distanceMatrix[i][k] == infinityDistance {
+				continue
+			}
+			for j := range wm.walkBoxes {
+				if distanceMatrix[k][j] == infinityDistance {
+					continue
+				}
+				if dist := distanceMatrix[i][k] + distanceMatrix[k][j]; dist < distanceMatrix[i][j] {
+					distanceMatrix[i][j] = dist
+					itineraryMatrix[i][j] = itineraryMatrix[i][k]
 				}
 			}
 		}
@@ -175,11 +434,122 @@ func (wm *WalkBoxMatrix) EnableWalkBox(id int, enabled bool) {
 	}
 }
 
+// IsWalkable returns true if the given position is inside an enabled walk box of the matrix.
+func (wm *WalkBoxMatrix) IsWalkable(p *Positionf) bool {
+	_, included := wm.walkBoxAt(p)
+	return included
+}
+
+// Validate checks the consistency of the walk boxes of the matrix. It returns an error for each
+// enabled walk box that has no enabled neighbors, and for each one that cannot be reached from
+// the rest of the walkable area.
+func (wm *WalkBoxMatrix) Validate() []error {
+	var enabled []int
+	for i, walkbox := range wm.walkBoxes {
+		if walkbox.enabled {
+			enabled = append(enabled, i)
+		}
+	}
+	if len(enabled) < 2 {
+		return nil
+	}
+
+	var errs []error
+	var connected []int
+	for _, i := range enabled {
+		if !slices.ContainsFunc(enabled, func(j int) bool {
+			return i != j && wm.walkBoxes[i].IsAdjacent(wm.walkBoxes[j])
+		}) {
+			errs = append(errs, fmt.Errorf("walkbox %s is isolated", wm.walkBoxes[i].walkBoxID))
+			continue
+		}
+		connected = append(connected, i)
+	}
+	for _, i := range connected {
+		if wm.nextWalkBox(connected[0], i) == InvalidWalkBox {
+			errs = append(errs, fmt.Errorf(
+				"walkbox %s is not reachable from walkbox %s",
+				wm.walkBoxes[i].walkBoxID, wm.walkBoxes[connected[0]].walkBoxID,
+			))
+		}
+	}
+	return errs
+}
+
+// WalkBoxIndex returns the position in the matrix of the walk box with the given identifier, or
+// InvalidWalkBox if there is no such walk box.
+func (wm *WalkBoxMatrix) WalkBoxIndex(id string) int {
+	for i, walkbox := range wm.walkBoxes {
+		if walkbox.walkBoxID == id {
+			return i
+		}
+	}
+	return InvalidWalkBox
+}
+
 // FindPath calculates and returns a path as a sequence of positions from the
 // starting point 'from' to the destination 'to' within the walk box matrix.
 // The path is returned as a slice of positions representing waypoints.
+//
+// The starting point is not included in the path. If 'from' is out of the walkable area, the
+// first waypoint will be the closest position inside it. If 'to' is out of the walkable area or
+// it is not reachable from 'from', the last waypoint will be the closest reachable position to
+// it. If there are no enabled walk boxes, the path goes straight to the destination.
 func (wm *WalkBoxMatrix) FindPath(from, to *Positionf) []*Positionf {
-	panic("Not implemented yet!")
+	fromBox, fromIncluded := wm.walkBoxAt(from)
+	if fromBox == InvalidWalkBox {
+		return []*Positionf{to}
+	}
+
+	var path []*Positionf
+	if !fromIncluded {
+		from = wm.closestPositionOnWalkBox(from)
+		path = append(path, from)
+	}
+
+	toBox, toIncluded := wm.walkBoxAt(to)
+	if wm.nextWalkBox(fromBox, toBox) == InvalidWalkBox {
+		toBox = wm.closestReachableWalkBox(fromBox, to)
+		toIncluded = false
+	}
+	if !toIncluded {
+		to, _ = wm.walkBoxes[toBox].closestPoint(to)
+	}
+
+	// Collect the gates to cross in the way to the destination box, and pull the path through
+	// them as a string, so the actor walks in straight lines across several boxes.
+	portals := []walkBoxPortal{{from, from}}
+	for box, hops := fromBox, 0; box != toBox && hops < len(wm.walkBoxes); hops++ {
+		next := wm.nextWalkBox(box, toBox)
+		portals = append(portals, wm.portal(box, next))
+		box = next
+	}
+	portals = append(portals, walkBoxPortal{to, to})
+	path = append(path, pullString(portals)...)
+
+	return removeDuplicatedWaypoints(path)
+}
+
+// DepthAt returns the scale and the speed factor of an actor standing in the given position. The
+// values are taken from the walk box at that position, or the closest one if the position is out
+// of the walkable area. If there are no enabled walk boxes, the scale and speed factor are 1.
+func (wm *WalkBoxMatrix) DepthAt(p *Positionf) (scale, speed float32) {
+	id, _ := wm.walkBoxAt(p)
+	if id == InvalidWalkBox {
+		return 1, 1
+	}
+	return wm.walkBoxes[id].ScaleAt(p.Y), wm.walkBoxes[id].speed
+}
+
+// ZPlanesAt returns the IDs of the z-planes declared by the walk box at the given position, or the
+// closest one if the position is out of the walkable area. It returns nil if there are no enabled
+// walk boxes or the walk box declares no z-planes.
+func (wm *WalkBoxMatrix) ZPlanesAt(p *Positionf) []string {
+	id, _ := wm.walkBoxAt(p)
+	if id == InvalidWalkBox {
+		return nil
+	}
+	return wm.walkBoxes[id].zplanes
 }
 
 // nextWalkBox returns the next walk box in the path from the source to the destination.
@@ -193,16 +563,126 @@ func (wm *WalkBoxMatrix) nextWalkBox(from, to int) int {
 // walkBoxAt returns the walk box identifier at the given position or the closest one,
 // along with a boolean indicating inclusion.
 func (wm *WalkBoxMatrix) walkBoxAt(p *Positionf) (id int, included bool) {
-	panic("Not implemented yet!")
+	id = InvalidWalkBox
+	var distance float32
+	for i, walkbox := range wm.walkBoxes {
+		if !walkbox.enabled {
+			continue
+		}
+		_, d := walkbox.closestPoint(p)
+		if id == InvalidWalkBox || d < distance {
+			id, distance = i, d
+		}
+	}
+	return id, id != InvalidWalkBox && distance < walkBoxEpsilon
 }
 
-// closestPositionToWalkBox returns the closest point to the specified walkbox identifiers from
-// the origin.
-func (wm *WalkBoxMatrix) closestPositionToWalkBox(from, to int) *Positionf {
-	panic("Not implemented yet!")
+// closestReachableWalkBox returns the walk box reachable from the given one that is closest to
+// the position p.
+func (wm *WalkBoxMatrix) closestReachableWalkBox(from int, p *Positionf) int {
+	id := from
+	_, distance := wm.walkBoxes[from].closestPoint(p)
+	for i, walkbox := range wm.walkBoxes {
+		if !walkbox.enabled || wm.nextWalkBox(from, i) == InvalidWalkBox {
+			continue
+		}
+		if _, d := walkbox.closestPoint(p); d < distance {
+			id, distance = i, d
+		}
+	}
+	return id
+}
+
+// portal returns the gate to cross from the walk box 'from' to the adjacent walk box 'to', seen
+// from the center of the former. The left and right ends are oriented so that a walker heading
+// towards the next box has the left end on its left and the right end on its right.
+func (wm *WalkBoxMatrix) portal(from, to int) walkBoxPortal {
+	a, b := wm.walkBoxes[from].gate(wm.walkBoxes[to])
+	if a == nil {
+		closest, _ := wm.walkBoxes[to].closestPoint(wm.walkBoxes[from].center())
+		return walkBoxPortal{closest, closest}
+	}
+	if triangleArea2(wm.walkBoxes[from].center(), a, b) < 0 {
+		a, b = b, a
+	}
+	return walkBoxPortal{a, b}
 }
 
 // closestPositionOnWalkBox returns the closest point on the walk box at a given position.
 func (wm *WalkBoxMatrix) closestPositionOnWalkBox(p *Positionf) *Positionf {
-	panic("Not implemented yet!")
+	id, _ := wm.walkBoxAt(p)
+	if id == InvalidWalkBox {
+		return p
+	}
+	closest, _ := wm.walkBoxes[id].closestPoint(p)
+	return closest
+}
+
+func removeDuplicatedWaypoints(path []*Positionf) []*Positionf {
+	result := make([]*Positionf, 0, len(path))
+	for _, p := range path {
+		if n := len(result); n > 0 && result[n-1].DistanceTo(p) < walkBoxEpsilon {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+// walkBoxPortal is a segment to be crossed in the way from one walk box to another.
+type walkBoxPortal struct {
+	left, right *Positionf
+}
+
+// pullString returns the shortest path that goes through the given portals, from the first one
+// to the last one, using the simple stupid funnel algorithm. The first and last portals are
+// expected to be the origin and destination points. The origin is not included in the path.
+func pullString(portals []walkBoxPortal) []*Positionf {
+	var path []*Positionf
+	apex, left, right := portals[0].left, portals[0].left, portals[0].right
+	apexIndex, leftIndex, rightIndex := 0, 0, 0
+
+	for i := 1; i < len(portals); i++ {
+		portal := portals[i]
+
+		// Update the right side of the funnel.
+		if triangleArea2(apex, right, portal.right) <= 0 {
+			if apex.Equals(right) || triangleArea2(apex, left, portal.right) > 0 {
+				// Tighten the funnel.
+				right, rightIndex = portal.right, i
+			} else {
+				// Right over left: the left point becomes the new apex and the scan restarts.
+				path = append(path, left)
+				apex, apexIndex = left, leftIndex
+				left, leftIndex = apex, apexIndex
+				right, rightIndex = apex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+
+		// Update the left side of the funnel.
+		if triangleArea2(apex, left, portal.left) >= 0 {
+			if apex.Equals(left) || triangleArea2(apex, right, portal.left) < 0 {
+				// Tighten the funnel.
+				left, leftIndex = portal.left, i
+			} else {
+				// Left over right: the right point becomes the new apex and the scan restarts.
+				path = append(path, right)
+				apex, apexIndex = right, rightIndex
+				left, leftIndex = apex, apexIndex
+				right, rightIndex = apex, apexIndex
+				i = apexIndex
+				continue
+			}
+		}
+	}
+
+	return append(path, portals[len(portals)-1].left)
+}
+
+// triangleArea2 returns the signed double area of the triangle a, b, c. Its sign indicates the
+// side of the segment a->b where c lies.
+func triangleArea2(a, b, c *Positionf) float32 {
+	return (c.X-a.X)*(b.Y-a.Y) - (b.X-a.X)*(c.Y-a.Y)
 }
@@ -1,10 +1,12 @@
 package pctk_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/apoloval/pctk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -14,25 +16,43 @@ const (
 func TestNewWalkBox(t *testing.T) {
 	testCases := []struct {
 		name        string
-		vertices    [4]*pctk.Positionf
+		vertices    []*pctk.Positionf
 		shouldPanic bool
 		message     string
 	}{
 		{
 			name:        "Concave polygon should panic",
-			vertices:    [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 2, Y: 1}, {X: 4, Y: 4}},
+			vertices:    []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 2, Y: 1}, {X: 4, Y: 4}},
 			shouldPanic: true,
 			message:     "Expected panic because vertices form a concave polygon!",
 		},
 		{
 			name:        "Collinear vertices should panic",
-			vertices:    [4]*pctk.Positionf{{X: 1, Y: 2}, {X: 2, Y: 4}, {X: 3, Y: 6}, {X: 4, Y: 8}},
+			vertices:    []*pctk.Positionf{{X: 1, Y: 2}, {X: 2, Y: 4}, {X: 3, Y: 6}, {X: 4, Y: 8}},
 			shouldPanic: true,
 			message:     "Expected panic because vertices are collinear!",
 		},
+		{
+			name:        "Less than three vertices should panic",
+			vertices:    []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}},
+			shouldPanic: true,
+			message:     "Expected panic because two vertices do not form a polygon!",
+		},
+		{
+			name:        "Should successfully create a valid WalkBox with a triangle",
+			vertices:    []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 2, Y: 4}},
+			shouldPanic: false,
+			message:     "Expected create a valid WalkBox, vertices form a triangle!",
+		},
+		{
+			name:        "Should successfully create a valid WalkBox with a pentagon",
+			vertices:    []*pctk.Positionf{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			shouldPanic: false,
+			message:     "Expected create a valid WalkBox, vertices form a convex pentagon!",
+		},
 		{
 			name:        "Should successfully create a valid WalkBox with a convex polygon",
-			vertices:    [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices:    []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			shouldPanic: false,
 			message:     "Expected create a valid WalkBox, vertices form a convex polygon!",
 		},
@@ -57,13 +77,13 @@ func TestNewWalkBox(t *testing.T) {
 func TestContainsPoint(t *testing.T) {
 	testCases := []struct {
 		name       string
-		vertices   [4]*pctk.Positionf
+		vertices   []*pctk.Positionf
 		point      *pctk.Positionf
 		assertFunc func(t *testing.T, isInside bool)
 	}{
 		{
 			name:     "The point should be considered inside the polygon when it is on the edge",
-			vertices: [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices: []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			point:    &pctk.Positionf{X: 2, Y: 0}, // On the edge
 			assertFunc: func(t *testing.T, isInside bool) {
 				assert.True(t, isInside)
@@ -71,7 +91,7 @@ func TestContainsPoint(t *testing.T) {
 		},
 		{
 			name:     "The point should be inside the polygon",
-			vertices: [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices: []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			point:    &pctk.Positionf{X: 2, Y: 2},
 			assertFunc: func(t *testing.T, isInside bool) {
 				assert.True(t, isInside)
@@ -79,7 +99,7 @@ func TestContainsPoint(t *testing.T) {
 		},
 		{
 			name:     "The point should be outside the polygon",
-			vertices: [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices: []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			point:    &pctk.Positionf{X: 5, Y: 5},
 			assertFunc: func(t *testing.T, isInside bool) {
 				assert.False(t, isInside)
@@ -87,7 +107,7 @@ func TestContainsPoint(t *testing.T) {
 		},
 		{
 			name:     "The point should be considered inside the polygon when it is on a vertex",
-			vertices: [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices: []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			point:    &pctk.Positionf{X: 0, Y: 0}, // On the vertex
 			assertFunc: func(t *testing.T, isInside bool) {
 				assert.True(t, isInside)
@@ -95,7 +115,7 @@ func TestContainsPoint(t *testing.T) {
 		},
 		{
 			name:     "The point should be outside when it is far from the polygon",
-			vertices: [4]*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
+			vertices: []*pctk.Positionf{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4}},
 			point:    &pctk.Positionf{X: 10, Y: 10}, // Clearly outside the polygon
 			assertFunc: func(t *testing.T, isInside bool) {
 				assert.False(t, isInside)
@@ -111,6 +131,98 @@ func TestContainsPoint(t *testing.T) {
 	}
 }
 
+func TestContainsPointWithManyVertices(t *testing.T) {
+	// A hexagon with vertices in counter-clockwise order.
+	walkBox := pctk.NewWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 2, Y: 0}, {X: 6, Y: 0}, {X: 8, Y: 4}, {X: 6, Y: 8}, {X: 2, Y: 8}, {X: 0, Y: 4},
+	})
+
+	assert.True(t, walkBox.ContainsPoint(&pctk.Positionf{X: 4, Y: 4}))
+	assert.True(t, walkBox.ContainsPoint(&pctk.Positionf{X: 1, Y: 4}))
+	assert.False(t, walkBox.ContainsPoint(&pctk.Positionf{X: 0.5, Y: 0.5}))
+	assert.False(t, walkBox.ContainsPoint(&pctk.Positionf{X: 7.5, Y: 7.5}))
+}
+
+func TestValidateWalkBox(t *testing.T) {
+	assert.NoError(t, pctk.ValidateWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
+	}))
+	assert.ErrorContains(t, pctk.ValidateWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 2, Y: 1}, {X: 4, Y: 4},
+	}), "convex")
+	assert.ErrorContains(t, pctk.ValidateWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 0}, {X: 4, Y: 0},
+	}), "at least 3 vertices")
+
+	// The first vertices are collinear, so the direction is taken from the next ones.
+	assert.NoError(t, pctk.ValidateWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}, {X: 0, Y: 4},
+	}))
+
+	// A pentagram turns always to the same side, but twice around its center.
+	assert.ErrorContains(t, pctk.ValidateWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 50, Y: 0}, {X: 79, Y: 90}, {X: 2, Y: 35}, {X: 98, Y: 35}, {X: 21, Y: 90},
+	}), "convex")
+}
+
+func TestWalkBoxBinaryEncodeDecode(t *testing.T) {
+	walkBox := pctk.NewWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 6, Y: 2}, {X: 4, Y: 4}, {X: 0, Y: 4},
+	}).WithScale(0.5, 0.8).WithSpeed(0.6).WithZPlanes("lamp", "fence")
+
+	var buf bytes.Buffer
+	_, err := pctk.BinaryEncode(&buf, walkBox)
+	require.NoError(t, err)
+
+	decoded := new(pctk.WalkBox)
+	require.NoError(t, pctk.BinaryDecode(&buf, decoded))
+	assert.Equal(t, walkBox.ID(), decoded.ID())
+	assert.Equal(t, walkBox.IsEnabled(), decoded.IsEnabled())
+	assert.Equal(t, walkBox.Vertices(), decoded.Vertices())
+	assert.Equal(t, walkBox.ScaleAt(2), decoded.ScaleAt(2))
+	assert.Equal(t, walkBox.Speed(), decoded.Speed())
+	assert.Equal(t, walkBox.ZPlanes(), decoded.ZPlanes())
+}
+
+func TestWalkBoxScaleAt(t *testing.T) {
+	walkBox := pctk.NewWalkBox(DefaultWalkBoxID, []*pctk.Positionf{
+		{X: 0, Y: 100}, {X: 40, Y: 100}, {X: 40, Y: 140}, {X: 0, Y: 140},
+	}).WithScale(0.5, 1)
+
+	assert.InDelta(t, 0.5, walkBox.ScaleAt(100), 0.001)
+	assert.InDelta(t, 0.75, walkBox.ScaleAt(120), 0.001)
+	assert.InDelta(t, 1, walkBox.ScaleAt(140), 0.001)
+	assert.InDelta(t, 0.5, walkBox.ScaleAt(50), 0.001, "above the top edge")
+	assert.InDelta(t, 1, walkBox.ScaleAt(200), 0.001, "below the bottom edge")
+}
+
+func TestWalkBoxMatrixDepthAt(t *testing.T) {
+	matrix := pctk.NewWalkBoxMatrix([]*pctk.WalkBox{
+		pctk.NewWalkBox("far", []*pctk.Positionf{{0, 0}, {10, 0}, {10, 10}, {0, 10}}).
+			WithScale(0.5, 0.7).
+			WithSpeed(0.5),
+		pctk.NewWalkBox("near", []*pctk.Positionf{{0, 10}, {10, 10}, {10, 20}, {0, 20}}),
+	})
+
+	testCases := []struct {
+		name          string
+		pos           pctk.Positionf
+		expectedScale float32
+		expectedSpeed float32
+	}{
+		{"Position in a scaled walkbox", pctk.NewPosf(5, 5), 0.6, 0.5},
+		{"Position in a walkbox with default values", pctk.NewPosf(5, 15), 1, 1},
+		{"Position out of the walkable area", pctk.NewPosf(5, -5), 0.5, 0.5},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			scale, speed := matrix.DepthAt(&testCase.pos)
+			assert.InDelta(t, testCase.expectedScale, scale, 0.001)
+			assert.InDelta(t, testCase.expectedSpeed, speed, 0.001)
+		})
+	}
+}
+
 func TestWalkBoxIsAdjacent(t *testing.T) {
 	/*
 		Polygons disposition:
@@ -144,14 +256,14 @@ func TestWalkBoxIsAdjacent(t *testing.T) {
 		- box7 is adjacent to box4, box6 (taller and positioned above box4)
 	*/
 
-	box0 := pctk.NewWalkBox("walkbox0", [4]*pctk.Positionf{{0, 0}, {1, 0}, {1, 1}, {0, 1}})
-	box1 := pctk.NewWalkBox("walkbox1", [4]*pctk.Positionf{{1, 0}, {2, 0}, {2, 1}, {1, 1}})
-	box2 := pctk.NewWalkBox("walkbox2", [4]*pctk.Positionf{{2, 0}, {3, 0}, {3, 1}, {2, 1}})
-	box3 := pctk.NewWalkBox("walkbox3", [4]*pctk.Positionf{{0, 1}, {1, 1}, {1, 2}, {0, 2}})
-	box4 := pctk.NewWalkBox("walkbox4", [4]*pctk.Positionf{{1, 1}, {2, 1}, {2, 2}, {1, 2}})
-	box5 := pctk.NewWalkBox("walkbox5", [4]*pctk.Positionf{{2, 1}, {3, 1}, {3, 2}, {2, 2}})
-	box6 := pctk.NewWalkBox("walkbox6", [4]*pctk.Positionf{{0, 3}, {1, 3}, {1, 4}, {0, 4}})
-	box7 := pctk.NewWalkBox("walkbox7", [4]*pctk.Positionf{{1, 2}, {2, 2}, {2, 5}, {1, 5}})
+	box0 := pctk.NewWalkBox("walkbox0", []*pctk.Positionf{{0, 0}, {1, 0}, {1, 1}, {0, 1}})
+	box1 := pctk.NewWalkBox("walkbox1", []*pctk.Positionf{{1, 0}, {2, 0}, {2, 1}, {1, 1}})
+	box2 := pctk.NewWalkBox("walkbox2", []*pctk.Positionf{{2, 0}, {3, 0}, {3, 1}, {2, 1}})
+	box3 := pctk.NewWalkBox("walkbox3", []*pctk.Positionf{{0, 1}, {1, 1}, {1, 2}, {0, 2}})
+	box4 := pctk.NewWalkBox("walkbox4", []*pctk.Positionf{{1, 1}, {2, 1}, {2, 2}, {1, 2}})
+	box5 := pctk.NewWalkBox("walkbox5", []*pctk.Positionf{{2, 1}, {3, 1}, {3, 2}, {2, 2}})
+	box6 := pctk.NewWalkBox("walkbox6", []*pctk.Positionf{{0, 3}, {1, 3}, {1, 4}, {0, 4}})
+	box7 := pctk.NewWalkBox("walkbox7", []*pctk.Positionf{{1, 2}, {2, 2}, {2, 5}, {1, 5}})
 
 	assert.True(t, box0.IsAdjacent(box1), "box0 should be adjacent to box1")
 	assert.True(t, box0.IsAdjacent(box3), "box0 should be adjacent to box3")
@@ -179,3 +291,186 @@ func TestWalkBoxIsAdjacent(t *testing.T) {
 	assert.True(t, box7.IsAdjacent(box6), "box7 should be adjacent to box6")
 
 }
+
+func TestWalkBoxMatrixFindPath(t *testing.T) {
+	/*
+		Polygons disposition:
+
+		  +-------+-------+-------+
+		  |       |       |       |
+		  | box0  | box1  | box2  |
+		  |       |       |       |
+		  +-------+-------+-------+
+		            |   |
+		            |box|
+		            | 3 |
+		            +---+
+	*/
+	newMatrix := func() *pctk.WalkBoxMatrix {
+		return pctk.NewWalkBoxMatrix([]*pctk.WalkBox{
+			pctk.NewWalkBox("walkbox0", []*pctk.Positionf{{0, 0}, {10, 0}, {10, 10}, {0, 10}}),
+			pctk.NewWalkBox("walkbox1", []*pctk.Positionf{{10, 0}, {20, 0}, {20, 10}, {10, 10}}),
+			pctk.NewWalkBox("walkbox2", []*pctk.Positionf{{20, 0}, {30, 0}, {30, 10}, {20, 10}}),
+			pctk.NewWalkBox("walkbox3", []*pctk.Positionf{{12, 10}, {18, 10}, {18, 20}, {12, 20}}),
+		})
+	}
+
+	testCases := []struct {
+		name     string
+		disabled []int
+		from     pctk.Positionf
+		to       pctk.Positionf
+		expected []pctk.Positionf
+	}{
+		{
+			name:     "Path in the same walkbox goes straight to the destination",
+			from:     pctk.Positionf{X: 2, Y: 2},
+			to:       pctk.Positionf{X: 8, Y: 6},
+			expected: []pctk.Positionf{{X: 8, Y: 6}},
+		},
+		{
+			name:     "Path goes straight across several walkboxes",
+			from:     pctk.Positionf{X: 5, Y: 5},
+			to:       pctk.Positionf{X: 25, Y: 5},
+			expected: []pctk.Positionf{{X: 25, Y: 5}},
+		},
+		{
+			name:     "Path turns left around the corners",
+			from:     pctk.Positionf{X: 5, Y: 2},
+			to:       pctk.Positionf{X: 15, Y: 18},
+			expected: []pctk.Positionf{{X: 12, Y: 10}, {X: 15, Y: 18}},
+		},
+		{
+			name:     "Path turns right around the corners",
+			from:     pctk.Positionf{X: 28, Y: 2},
+			to:       pctk.Positionf{X: 13, Y: 19},
+			expected: []pctk.Positionf{{X: 18, Y: 10}, {X: 13, Y: 19}},
+		},
+		{
+			name:     "Path cuts the corners when there is a straight line",
+			from:     pctk.Positionf{X: 7, Y: 1},
+			to:       pctk.Positionf{X: 17, Y: 16},
+			expected: []pctk.Positionf{{X: 17, Y: 16}},
+		},
+		{
+			name:     "Destination out of the walkable area ends in the closest position",
+			from:     pctk.Positionf{X: 15, Y: 5},
+			to:       pctk.Positionf{X: 35, Y: 5},
+			expected: []pctk.Positionf{{X: 30, Y: 5}},
+		},
+		{
+			name:     "Origin out of the walkable area starts in the closest position",
+			from:     pctk.Positionf{X: 5, Y: -5},
+			to:       pctk.Positionf{X: 5, Y: 5},
+			expected: []pctk.Positionf{{X: 5, Y: 0}, {X: 5, Y: 5}},
+		},
+		{
+			name:     "Unreachable destination ends in the closest reachable position",
+			disabled: []int{1},
+			from:     pctk.Positionf{X: 5, Y: 5},
+			to:       pctk.Positionf{X: 25, Y: 5},
+			expected: []pctk.Positionf{{X: 10, Y: 5}},
+		},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			matrix := newMatrix()
+			for _, id := range testCase.disabled {
+				matrix.EnableWalkBox(id, false)
+			}
+			path := matrix.FindPath(&testCase.from, &testCase.to)
+			require.Len(t, path, len(testCase.expected))
+			for i, expected := range testCase.expected {
+				assert.InDelta(t, expected.X, path[i].X, 0.01, "waypoint %d", i)
+				assert.InDelta(t, expected.Y, path[i].Y, 0.01, "waypoint %d", i)
+			}
+		})
+	}
+}
+
+func TestWalkBoxMatrixFindPathPrefersShorterRoutes(t *testing.T) {
+	/*
+		Polygons disposition:
+
+		  +---+---+---+---+---+
+		  | 0 | 1 | 2 | 3 | 4 |
+		  +---+---+---+---+---+
+		  |                   |
+		  |     walkbox5      |
+		  |                   |
+		  +-------------------+
+
+		Going from box0 to box4 through box5 takes less boxes, but it is a much longer walk.
+	*/
+	matrix := pctk.NewWalkBoxMatrix([]*pctk.WalkBox{
+		pctk.NewWalkBox("walkbox0", []*pctk.Positionf{{0, 0}, {10, 0}, {10, 10}, {0, 10}}),
+		pctk.NewWalkBox("walkbox1", []*pctk.Positionf{{10, 0}, {20, 0}, {20, 10}, {10, 10}}),
+		pctk.NewWalkBox("walkbox2", []*pctk.Positionf{{20, 0}, {30, 0}, {30, 10}, {20, 10}}),
+		pctk.NewWalkBox("walkbox3", []*pctk.Positionf{{30, 0}, {40, 0}, {40, 10}, {30, 10}}),
+		pctk.NewWalkBox("walkbox4", []*pctk.Positionf{{40, 0}, {50, 0}, {50, 10}, {40, 10}}),
+		pctk.NewWalkBox("walkbox5", []*pctk.Positionf{{0, 10}, {50, 10}, {50, 100}, {0, 100}}),
+	})
+	from, to := pctk.NewPosf(5, 5), pctk.NewPosf(45, 5)
+
+	path := matrix.FindPath(&from, &to)
+
+	require.Len(t, path, 1)
+	assert.Equal(t, to, *path[0])
+}
+
+func TestWalkBoxMatrixValidate(t *testing.T) {
+	/*
+		Polygons disposition:
+
+		  +-------+-------+       +-------+-------+
+		  |       |       |       |       |       |
+		  | box0  | box1  |       | box3  | box4  |
+		  |       |       |       |       |       |
+		  +-------+-------+       +-------+-------+
+
+		                  +-------+
+		                  | box2  |
+		                  +-------+
+	*/
+	matrix := pctk.NewWalkBoxMatrix([]*pctk.WalkBox{
+		pctk.NewWalkBox("walkbox0", []*pctk.Positionf{{0, 0}, {10, 0}, {10, 10}, {0, 10}}),
+		pctk.NewWalkBox("walkbox1", []*pctk.Positionf{{10, 0}, {20, 0}, {20, 10}, {10, 10}}),
+		pctk.NewWalkBox("walkbox2", []*pctk.Positionf{{20, 20}, {30, 20}, {30, 30}, {20, 30}}),
+		pctk.NewWalkBox("walkbox3", []*pctk.Positionf{{30, 0}, {40, 0}, {40, 10}, {30, 10}}),
+		pctk.NewWalkBox("walkbox4", []*pctk.Positionf{{40, 0}, {50, 0}, {50, 10}, {40, 10}}),
+	})
+
+	errs := matrix.Validate()
+
+	require.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], "walkbox2 is isolated")
+	assert.ErrorContains(t, errs[1], "walkbox3 is not reachable from walkbox walkbox0")
+	assert.ErrorContains(t, errs[2], "walkbox4 is not reachable from walkbox walkbox0")
+
+	matrix.EnableWalkBox(2, false)
+	matrix.EnableWalkBox(3, false)
+	matrix.EnableWalkBox(4, false)
+	assert.Empty(t, matrix.Validate())
+}
+
+func TestWalkBoxMatrixFindPathWithoutWalkBoxes(t *testing.T) {
+	matrix := pctk.NewWalkBoxMatrix(nil)
+	from, to := pctk.NewPosf(0, 0), pctk.NewPosf(100, 100)
+
+	path := matrix.FindPath(&from, &to)
+
+	require.Len(t, path, 1)
+	assert.Equal(t, to, *path[0])
+}
+
+func TestWalkBoxMatrixZPlanesAt(t *testing.T) {
+	matrix := pctk.NewWalkBoxMatrix([]*pctk.WalkBox{
+		pctk.NewWalkBox("behind", []*pctk.Positionf{{0, 0}, {10, 0}, {10, 10}, {0, 10}}).
+			WithZPlanes("counter"),
+		pctk.NewWalkBox("front", []*pctk.Positionf{{0, 10}, {10, 10}, {10, 20}, {0, 20}}),
+	})
+
+	assert.Equal(t, []string{"counter"}, matrix.ZPlanesAt(&pctk.Positionf{X: 5, Y: 5}))
+	assert.Nil(t, matrix.ZPlanesAt(&pctk.Positionf{X: 5, Y: 15}))
+	assert.Equal(t, []string{"counter"}, matrix.ZPlanesAt(&pctk.Positionf{X: 5, Y: -5}), "closest walk box")
+}